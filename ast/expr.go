@@ -15,6 +15,20 @@ type ExprVisitor interface {
 	VisitExpr_Unary(e Expr_Unary) any
 	VisitExpr_Variable(e Expr_Variable) any
 	VisitExpr_Logical(e Expr_Logical) any
+	VisitExpr_List(e Expr_List) any
+	VisitExpr_Map(e Expr_Map) any
+	VisitExpr_SetLiteral(e Expr_SetLiteral) any
+	VisitExpr_Index(e Expr_Index) any
+	VisitExpr_IndexSet(e Expr_IndexSet) any
+	VisitExpr_Get(e Expr_Get) any
+	VisitExpr_Set(e Expr_Set) any
+	VisitExpr_Tuple(e Expr_Tuple) any
+	VisitExpr_Range(e Expr_Range) any
+	VisitExpr_Spread(e Expr_Spread) any
+	VisitExpr_Spawn(e Expr_Spawn) any
+	VisitExpr_Await(e Expr_Await) any
+	VisitExpr_ListComprehension(e Expr_ListComprehension) any
+	VisitExpr_MapComprehension(e Expr_MapComprehension) any
 }
 
 // Expr_Binary struct
@@ -94,3 +108,156 @@ type Expr_Logical struct {
 func (e Expr_Logical) Accept(Visitor ExprVisitor) any {
 	return Visitor.VisitExpr_Logical(e)
 }
+
+// Expr_List struct
+type Expr_List struct {
+	Bracket  Token
+	Elements []Expr
+}
+
+func (e Expr_List) Accept(Visitor ExprVisitor) any {
+	return Visitor.VisitExpr_List(e)
+}
+
+// Expr_Map struct
+type Expr_Map struct {
+	Brace  Token
+	Keys   []Expr
+	Values []Expr
+}
+
+func (e Expr_Map) Accept(Visitor ExprVisitor) any {
+	return Visitor.VisitExpr_Map(e)
+}
+
+// Expr_SetLiteral struct
+type Expr_SetLiteral struct {
+	Brace    Token
+	Elements []Expr
+}
+
+func (e Expr_SetLiteral) Accept(Visitor ExprVisitor) any {
+	return Visitor.VisitExpr_SetLiteral(e)
+}
+
+// Expr_Index struct
+type Expr_Index struct {
+	Object  Expr
+	Bracket Token
+	Index   Expr
+}
+
+func (e Expr_Index) Accept(Visitor ExprVisitor) any {
+	return Visitor.VisitExpr_Index(e)
+}
+
+// Expr_IndexSet struct
+type Expr_IndexSet struct {
+	Object  Expr
+	Bracket Token
+	Index   Expr
+	Value   Expr
+}
+
+func (e Expr_IndexSet) Accept(Visitor ExprVisitor) any {
+	return Visitor.VisitExpr_IndexSet(e)
+}
+
+// Expr_Get struct
+type Expr_Get struct {
+	Object Expr
+	Name   Token
+}
+
+func (e Expr_Get) Accept(Visitor ExprVisitor) any {
+	return Visitor.VisitExpr_Get(e)
+}
+
+// Expr_Set struct
+type Expr_Set struct {
+	Object Expr
+	Name   Token
+	Value  Expr
+}
+
+func (e Expr_Set) Accept(Visitor ExprVisitor) any {
+	return Visitor.VisitExpr_Set(e)
+}
+
+// Expr_Tuple struct
+type Expr_Tuple struct {
+	Paren    Token
+	Elements []Expr
+}
+
+func (e Expr_Tuple) Accept(Visitor ExprVisitor) any {
+	return Visitor.VisitExpr_Tuple(e)
+}
+
+// Expr_Range struct
+type Expr_Range struct {
+	Start    Expr
+	Operator Token
+	End      Expr
+}
+
+func (e Expr_Range) Accept(Visitor ExprVisitor) any {
+	return Visitor.VisitExpr_Range(e)
+}
+
+// Expr_Spread struct
+type Expr_Spread struct {
+	Ellipsis   Token
+	Expression Expr
+}
+
+func (e Expr_Spread) Accept(Visitor ExprVisitor) any {
+	return Visitor.VisitExpr_Spread(e)
+}
+
+// Expr_Spawn struct
+type Expr_Spawn struct {
+	Keyword Token
+	Call    *Expr_Call
+}
+
+func (e Expr_Spawn) Accept(Visitor ExprVisitor) any {
+	return Visitor.VisitExpr_Spawn(e)
+}
+
+// Expr_Await struct
+type Expr_Await struct {
+	Keyword Token
+	Value   Expr
+}
+
+func (e Expr_Await) Accept(Visitor ExprVisitor) any {
+	return Visitor.VisitExpr_Await(e)
+}
+
+// Expr_ListComprehension struct
+type Expr_ListComprehension struct {
+	Bracket   Token
+	Element   Expr
+	Name      Token
+	Iterable  Expr
+	Condition Expr
+}
+
+func (e Expr_ListComprehension) Accept(Visitor ExprVisitor) any {
+	return Visitor.VisitExpr_ListComprehension(e)
+}
+
+// Expr_MapComprehension struct
+type Expr_MapComprehension struct {
+	Brace     Token
+	Key       Expr
+	Value     Expr
+	Name      Token
+	Iterable  Expr
+	Condition Expr
+}
+
+func (e Expr_MapComprehension) Accept(Visitor ExprVisitor) any {
+	return Visitor.VisitExpr_MapComprehension(e)
+}
@@ -7,10 +7,13 @@ type Stmt interface {
 type StmtVisitor interface {
 	VisitStmt_Block(e Stmt_Block)
 	VisitStmt_Expression(e Stmt_Expression)
+	VisitStmt_ForIn(e Stmt_ForIn)
 	VisitStmt_Function(e Stmt_Function)
 	VisitStmt_If(e Stmt_If)
 	VisitStmt_Print(e Stmt_Print)
+	VisitStmt_Record(e Stmt_Record)
 	VisitStmt_Return(e Stmt_Return)
+	VisitStmt_Select(e Stmt_Select)
 	VisitStmt_While(e Stmt_While)
 	VisitStmt_Var(e Stmt_Var)
 }
@@ -27,6 +30,7 @@ type Stmt_Function struct {
 	Name   Token
 	Params []Token
 	Body   []Stmt
+	Async  bool
 }
 
 func (e Stmt_Function) Accept(Visitor StmtVisitor) {
@@ -85,3 +89,44 @@ type Stmt_While struct {
 func (e Stmt_While) Accept(Visitor StmtVisitor) {
 	Visitor.VisitStmt_While(e)
 }
+
+type Stmt_ForIn struct {
+	Name     Token
+	Iterable Expr
+	Body     Stmt
+}
+
+func (e Stmt_ForIn) Accept(Visitor StmtVisitor) {
+	Visitor.VisitStmt_ForIn(e)
+}
+
+type Stmt_Record struct {
+	Name   Token
+	Fields []Token
+}
+
+func (e Stmt_Record) Accept(Visitor StmtVisitor) {
+	Visitor.VisitStmt_Record(e)
+}
+
+// SelectCase is one case of a select statement. Kind is the recv, send or
+// timeout that starts it, and Operand its channel or timeout duration. Value
+// is the value a send case sends, and Name the variable a recv case binds
+// the received value to, if any.
+type SelectCase struct {
+	Kind    Token
+	Name    *Token
+	Operand Expr
+	Value   Expr
+	Body    Stmt
+}
+
+type Stmt_Select struct {
+	Keyword Token
+	Cases   []SelectCase
+	Default Stmt
+}
+
+func (e Stmt_Select) Accept(Visitor StmtVisitor) {
+	Visitor.VisitStmt_Select(e)
+}
@@ -7,6 +7,9 @@ type Token struct {
 	Lexeme    string
 	Literal   any
 	Line      int
+	// Offset is the token's byte position in the source. It keeps tokens
+	// with the same lexeme on the same line distinct.
+	Offset int
 }
 
 func (t Token) ToString() string {
@@ -15,11 +18,13 @@ func (t Token) ToString() string {
 
 var tokenNames = []string{
 	"LEFT_PAREN", "RIGHT_PAREN", "LEFT_BRACE", "RIGHT_BRACE",
+	"LEFT_BRACKET", "RIGHT_BRACKET", "COLON",
 	"COMMA", "DOT", "MINUS", "PLUS", "SEMICOLON", "SLASH", "STAR",
-	"BANG", "BANG_EQUAL", "EQUAL", "EQUAL_EQUAL", "GREATER", "GREATER_EQUAL",
-	"LESS", "LESS_EQUAL", "IDENTIFIER", "STRING", "NUMBER", "AND", "CLASS",
-	"ELSE", "FALSE", "FUN", "FOR", "IF", "NIL", "OR", "PRINT", "RETURN",
-	"SUPER", "THIS", "TRUE", "VAR", "WHILE", "EOF",
+	"BANG", "BANG_EQUAL", "EQUAL", "EQUAL_EQUAL", "ARROW", "GREATER", "GREATER_EQUAL",
+	"LESS", "LESS_EQUAL", "DOT_DOT", "DOT_DOT_LESS",
+	"DOT_DOT_DOT", "IDENTIFIER", "STRING", "NUMBER", "AND", "ASYNC", "AWAIT", "CASE", "CLASS",
+	"ELSE", "FALSE", "FUN", "FOR", "IF", "IN", "NIL", "OR", "PRINT", "RECORD", "RETURN",
+	"SELECT", "SPAWN", "SUPER", "THIS", "TRUE", "VAR", "WHILE", "EOF",
 }
 
 func getTokenName(tokenType TokenType) string {
@@ -8,7 +8,10 @@ const (
 	RIGHT_PAREN
 	LEFT_BRACE
 	RIGHT_BRACE
+	LEFT_BRACKET
+	RIGHT_BRACKET
 
+	COLON
 	COMMA
 	DOT
 	MINUS
@@ -23,6 +26,7 @@ const (
 
 	EQUAL
 	EQUAL_EQUAL
+	ARROW
 
 	GREATER
 	GREATER_EQUAL
@@ -30,6 +34,10 @@ const (
 	LESS
 	LESS_EQUAL
 
+	DOT_DOT
+	DOT_DOT_LESS
+	DOT_DOT_DOT
+
 	// Literals.
 	IDENTIFIER
 	STRING
@@ -37,17 +45,24 @@ const (
 
 	// Keywords.
 	AND
+	ASYNC
+	AWAIT
+	CASE
 	CLASS
 	ELSE
 	FALSE
 	FUN
 	FOR
 	IF
+	IN
 	NIL
 	OR
 
 	PRINT
+	RECORD
 	RETURN
+	SELECT
+	SPAWN
 	SUPER
 	THIS
 	TRUE
@@ -0,0 +1,74 @@
+package interpret
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is where the interpreter gets the current time from, for clock(),
+// the time module, sleeping and select timeouts. Hosts can install their own with SetClock,
+// for example a FakeClock to make script tests deterministic.
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// FakeClock is a Clock that only moves when told to. Sleep returns at once
+// after advancing the clock by the requested duration, and After likewise
+// returns a channel that has already fired.
+type FakeClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+func (f *FakeClock) Now() time.Time {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.now
+}
+
+func (f *FakeClock) Sleep(d time.Duration) {
+	f.Advance(d)
+}
+
+func (f *FakeClock) After(d time.Duration) <-chan time.Time {
+	f.Advance(d)
+	fired := make(chan time.Time, 1)
+	fired <- f.Now()
+	return fired
+}
+
+// Advance moves the clock forward by d.
+func (f *FakeClock) Advance(d time.Duration) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.now = f.now.Add(d)
+}
+
+// SetClock replaces the interpreter's source of time. A nil clock restores
+// the system clock.
+func (i *Interpreter) SetClock(clock Clock) {
+	if clock == nil {
+		clock = systemClock{}
+	}
+	i.clock = clock
+}
@@ -0,0 +1,218 @@
+package interpret
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/kljablon/golox/utils"
+)
+
+// collect reads an iterable to the end. Its argument has already been
+// checked to be iterable.
+func collect(iterable any) []any {
+	iterator, _ := iteratorFor(iterable)
+	elements := []any{}
+	for {
+		value, ok := iterator.next()
+		if !ok {
+			return elements
+		}
+		elements = append(elements, value)
+	}
+}
+
+// compareValues orders two numbers, two strings, or two lists or tuples
+// element by element, returning a negative number, zero or a positive number.
+func compareValues(a any, b any) int {
+	switch x := a.(type) {
+	case float64:
+		if y, ok := b.(float64); ok {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y)
+		}
+	case *LoxTuple:
+		if y, ok := b.(*LoxTuple); ok {
+			return compareElements(x.elements, y.elements)
+		}
+	case *LoxList:
+		if y, ok := b.(*LoxList); ok {
+			return compareElements(x.elements, y.elements)
+		}
+	}
+	panic(NativeError{"Can't compare " + repr(a) + " and " + repr(b) + "."})
+}
+
+func compareElements(a []any, b []any) int {
+	for index := 0; index < len(a) && index < len(b); index++ {
+		if order := compareValues(a[index], b[index]); order != 0 {
+			return order
+		}
+	}
+	return len(a) - len(b)
+}
+
+// sortElements sorts elements in place, keeping equal elements in their
+// original order. A function taking two arguments is a comparator returning a
+// negative number, zero or a positive number; any other function is a key
+// function whose results are compared instead of the elements.
+func sortElements(interpreter *Interpreter, elements []any, function LoxCallable) {
+	if function == nil {
+		sort.SliceStable(elements, func(a, b int) bool {
+			return compareValues(elements[a], elements[b]) < 0
+		})
+		return
+	}
+	if minArity, _ := function.arity(); minArity == 2 {
+		sort.SliceStable(elements, func(a, b int) bool {
+			order, ok := callFunction(interpreter, function, []any{elements[a], elements[b]}).(float64)
+			if !ok {
+				panic(NativeError{"Comparator must return a number."})
+			}
+			return order < 0
+		})
+		return
+	}
+
+	keys := make([]any, len(elements))
+	for index, element := range elements {
+		keys[index] = callFunction(interpreter, function, []any{element})
+	}
+	order := make([]int, len(elements))
+	for index := range order {
+		order[index] = index
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return compareValues(keys[order[a]], keys[order[b]]) < 0
+	})
+	sorted := make([]any, len(elements))
+	for index, position := range order {
+		sorted[index] = elements[position]
+	}
+	copy(elements, sorted)
+}
+
+// satisfies applies an optional predicate to value, or else takes its
+// truthiness.
+func satisfies(interpreter *Interpreter, predicate LoxCallable, value any) bool {
+	if predicate != nil {
+		value = callFunction(interpreter, predicate, []any{value})
+	}
+	return utils.IsTruthy(value)
+}
+
+// optionalCallable returns the argument at position if it was given.
+func optionalCallable(arguments []any, position int) LoxCallable {
+	if len(arguments) > position {
+		return arguments[position].(LoxCallable)
+	}
+	return nil
+}
+
+func defineCollectionFunctions(i *Interpreter) {
+	i.DefineNative(native("map", []ArgType{CALLABLE_ARG, ITERABLE_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		function := arguments[0].(LoxCallable)
+		elements := collect(arguments[1])
+		for index, element := range elements {
+			elements[index] = callFunction(interpreter, function, []any{element})
+		}
+		return NewLoxList(elements)
+	}))
+	i.DefineNative(native("filter", []ArgType{CALLABLE_ARG, ITERABLE_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		predicate := arguments[0].(LoxCallable)
+		kept := []any{}
+		for _, element := range collect(arguments[1]) {
+			if satisfies(interpreter, predicate, element) {
+				kept = append(kept, element)
+			}
+		}
+		return NewLoxList(kept)
+	}))
+	i.DefineNative(NewNativeFunction("reduce", 2, 3, []ArgType{CALLABLE_ARG, ITERABLE_ARG, ANY_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		function := arguments[0].(LoxCallable)
+		elements := collect(arguments[1])
+		var accumulator any
+		if len(arguments) == 3 {
+			accumulator = arguments[2]
+		} else if len(elements) == 0 {
+			panic(NativeError{"Can't reduce an empty iterable without an initial value."})
+		} else {
+			accumulator, elements = elements[0], elements[1:]
+		}
+		for _, element := range elements {
+			accumulator = callFunction(interpreter, function, []any{accumulator, element})
+		}
+		return accumulator
+	}))
+	i.DefineNative(NewNativeFunction("any", 1, 2, []ArgType{ITERABLE_ARG, CALLABLE_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		predicate := optionalCallable(arguments, 1)
+		iterator, _ := iteratorFor(arguments[0])
+		defer releaseIterator(iterator)
+		for {
+			value, ok := iterator.next()
+			if !ok {
+				return false
+			}
+			if satisfies(interpreter, predicate, value) {
+				return true
+			}
+		}
+	}))
+	i.DefineNative(NewNativeFunction("all", 1, 2, []ArgType{ITERABLE_ARG, CALLABLE_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		predicate := optionalCallable(arguments, 1)
+		iterator, _ := iteratorFor(arguments[0])
+		defer releaseIterator(iterator)
+		for {
+			value, ok := iterator.next()
+			if !ok {
+				return true
+			}
+			if !satisfies(interpreter, predicate, value) {
+				return false
+			}
+		}
+	}))
+	i.DefineNative(NewNativeFunction("zip", 1, -1, []ArgType{ITERABLE_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		iterators := make([]LoxIterator, len(arguments))
+		for index, argument := range arguments {
+			iterators[index], _ = iteratorFor(argument)
+			defer releaseIterator(iterators[index])
+		}
+		zipped := []any{}
+		for {
+			tuple := make([]any, len(iterators))
+			for index, iterator := range iterators {
+				value, ok := iterator.next()
+				if !ok {
+					return NewLoxList(zipped)
+				}
+				tuple[index] = value
+			}
+			zipped = append(zipped, NewLoxTuple(tuple))
+		}
+	}))
+	i.DefineNative(NewNativeFunction("enumerate", 1, 2, []ArgType{ITERABLE_ARG, INTEGER_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		start := 0.0
+		if len(arguments) == 2 {
+			start = arguments[1].(float64)
+		}
+		elements := collect(arguments[0])
+		for index, element := range elements {
+			elements[index] = NewLoxTuple([]any{start + float64(index), element})
+		}
+		return NewLoxList(elements)
+	}))
+	i.DefineNative(NewNativeFunction("sort", 1, 2, []ArgType{ITERABLE_ARG, CALLABLE_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		elements := collect(arguments[0])
+		sortElements(interpreter, elements, optionalCallable(arguments, 1))
+		return NewLoxList(elements)
+	}))
+}
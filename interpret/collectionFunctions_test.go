@@ -0,0 +1,56 @@
+package interpret_test
+
+import "testing"
+
+func TestMapFilterReduce(t *testing.T) {
+	expectOutput(t, `
+fun double(x) { return x * 2; }
+fun even(x) { return x / 2 == math.floor(x / 2); }
+fun add(a, b) { return a + b; }
+print map(double, [1, 2, 3]);
+print map(double, Set([1]));
+print filter(even, 1..6);
+print reduce(add, [1, 2, 3], 10);
+print reduce(add, ["a", "b"]);
+print any([1, 3], even);
+print any([false, nil, 1]);
+print all([2, 4], even);
+print all([], even);
+`, "[2, 4, 6]\n[2]\n[2, 4, 6]\n16\nab\nfalse\ntrue\ntrue\ntrue\n", nil)
+}
+
+func TestZipAndEnumerate(t *testing.T) {
+	expectOutput(t, `
+print zip([1, 2, 3], ("a", "b"));
+print enumerate(["x", "y"]);
+print enumerate(["x"], 1);
+`, "[(1, \"a\"), (2, \"b\")]\n[(0, \"x\"), (1, \"y\")]\n[(1, \"x\")]\n", nil)
+}
+
+func TestSort(t *testing.T) {
+	expectOutput(t, `
+print sort([3, 1, 2]);
+print sort(["b", "a", "c"]);
+print sort([(2, "b"), (1, "z"), (2, "a")]);
+fun descending(a, b) { return b - a; }
+print sort([3, 1, 2], descending);
+fun length(s) { return s.length; }
+print sort(["ccc", "a", "bb", "d"], length);
+`, "[1, 2, 3]\n[\"a\", \"b\", \"c\"]\n[(1, \"z\"), (2, \"a\"), (2, \"b\")]\n[3, 2, 1]\n[\"a\", \"d\", \"bb\", \"ccc\"]\n", nil)
+}
+
+func TestCollectionFunctionErrors(t *testing.T) {
+	expectError(t, `
+map(1, [1]);
+`, "Argument 1 to map() must be a function.", 2, nil)
+	expectError(t, `
+fun pair(a, b) { return a; }
+map(pair, [1]);
+`, "Expected 2 arguments but got 1.", 3, nil)
+	expectError(t, `
+sort([1, "a"]);
+`, "Can't compare \"a\" and 1.", 2, nil)
+	expectError(t, `
+reduce(clock, []);
+`, "Can't reduce an empty iterable without an initial value.", 2, nil)
+}
@@ -0,0 +1,174 @@
+package interpret
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// csvOptions are read from the optional map passed to csv.parse and
+// csv.stringify.
+type csvOptions struct {
+	header    bool
+	columns   []any
+	separator rune
+}
+
+func readCsvOptions(function string, arguments []any) csvOptions {
+	options := csvOptions{separator: ','}
+	if len(arguments) < 2 {
+		return options
+	}
+	given := arguments[1].(*LoxMap)
+	for _, key := range given.keys {
+		value := given.valueOf(key)
+		switch key {
+		case "header":
+			switch v := value.(type) {
+			case bool:
+				options.header = v
+			case *LoxList:
+				options.header = true
+				options.columns = v.elements
+			default:
+				panic(NativeError{function + "() option 'header' must be a boolean or a list of column names."})
+			}
+		case "separator":
+			text, ok := value.(string)
+			if !ok || utf8.RuneCountInString(text) != 1 {
+				panic(NativeError{function + "() option 'separator' must be a single character."})
+			}
+			options.separator, _ = utf8.DecodeRuneInString(text)
+			switch options.separator {
+			case '"', '\r', '\n', utf8.RuneError:
+				panic(NativeError{function + "() option 'separator' can't be " + repr(text) + "."})
+			}
+		default:
+			panic(NativeError{"Unknown " + function + "() option " + repr(key) + "."})
+		}
+	}
+	return options
+}
+
+// csvParse reads CSV text into a list of rows. Each row is a list of strings,
+// or in header mode a map from the column names on the first line to the
+// row's fields.
+func csvParse(text string, options csvOptions) *LoxList {
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.Comma = options.separator
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		panic(NativeError{"Invalid CSV: " + err.Error() + "."})
+	}
+
+	rows := []any{}
+	if !options.header {
+		for _, record := range records {
+			fields := make([]any, len(record))
+			for index, field := range record {
+				fields[index] = field
+			}
+			rows = append(rows, NewLoxList(fields))
+		}
+		return NewLoxList(rows)
+	}
+
+	if len(records) == 0 {
+		return NewLoxList(rows)
+	}
+	header := records[0]
+	for number, record := range records[1:] {
+		if len(record) != len(header) {
+			panic(NativeError{fmt.Sprintf("CSV row %d has %d fields but the header has %d.", number+2, len(record), len(header))})
+		}
+		row := NewLoxMap()
+		for index, field := range record {
+			if _, ok := row.values[header[index]]; !ok {
+				row.keys = append(row.keys, header[index])
+			}
+			row.values[header[index]] = field
+		}
+		rows = append(rows, row)
+	}
+	return NewLoxList(rows)
+}
+
+// csvField converts a value to the text of a CSV field; nil is left empty.
+func csvField(value any) string {
+	if value == nil {
+		return ""
+	}
+	return stringify(value)
+}
+
+// csvStringify writes rows as CSV, quoting fields where needed. Rows are
+// lists, or maps when a header is written: the columns are then the keys of
+// the first row, unless they are given in the options.
+func csvStringify(rows []any, options csvOptions) string {
+	var out strings.Builder
+	writer := csv.NewWriter(&out)
+	writer.Comma = options.separator
+
+	columns := options.columns
+	if options.header && columns == nil && len(rows) > 0 {
+		if first, ok := rows[0].(*LoxMap); ok {
+			columns = first.keys
+		}
+	}
+	if options.header {
+		header := make([]string, len(columns))
+		for index, column := range columns {
+			header[index] = csvField(column)
+		}
+		writeCsvRecord(writer, header)
+	}
+
+	for number, row := range rows {
+		var record []string
+		switch v := row.(type) {
+		case *LoxList:
+			for _, value := range v.elements {
+				record = append(record, csvField(value))
+			}
+		case *LoxTuple:
+			for _, value := range v.elements {
+				record = append(record, csvField(value))
+			}
+		case *LoxMap:
+			if !options.header {
+				panic(NativeError{"Rows that are maps need the 'header' option."})
+			}
+			for _, column := range columns {
+				record = append(record, csvField(v.valueOf(column)))
+			}
+		default:
+			panic(NativeError{fmt.Sprintf("CSV row %d must be a list or a map.", number+1)})
+		}
+		writeCsvRecord(writer, record)
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		panic(NativeError{"Can't write CSV: " + err.Error() + "."})
+	}
+	return out.String()
+}
+
+func writeCsvRecord(writer *csv.Writer, record []string) {
+	if err := writer.Write(record); err != nil {
+		panic(NativeError{"Can't write CSV: " + err.Error() + "."})
+	}
+}
+
+func newCsvModule() *LoxModule {
+	module := NewLoxModule("csv")
+
+	module.DefineNative(NewNativeFunction("parse", 1, 2, []ArgType{STRING_ARG, MAP_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		return csvParse(arguments[0].(string), readCsvOptions("parse", arguments))
+	}))
+	module.DefineNative(NewNativeFunction("stringify", 1, 2, []ArgType{LIST_ARG, MAP_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		return csvStringify(arguments[0].(*LoxList).elements, readCsvOptions("stringify", arguments))
+	}))
+	return module
+}
@@ -0,0 +1,65 @@
+package interpret_test
+
+import "testing"
+
+const people = "name,city\nAda,\"London, UK\"\nBob,Paris\n"
+
+func TestCsvParse(t *testing.T) {
+	expectOutput(t, `
+print csv.parse(args[0]);
+var rows = csv.parse(args[0], {"header": true});
+print rows;
+print rows[1]["city"];
+print csv.parse("a;b", {"separator": ";"});
+print csv.parse("a,b
+c");
+`, `[["name", "city"], ["Ada", "London, UK"], ["Bob", "Paris"]]
+[{"name": "Ada", "city": "London, UK"}, {"name": "Bob", "city": "Paris"}]
+Paris
+[["a", "b"]]
+[["a", "b"], ["c"]]
+`, withArgs(people))
+}
+
+func TestCsvStringify(t *testing.T) {
+	expectOutput(t, `
+printf(csv.stringify([["a", "b,c"], (1, nil)]));
+var rows = csv.parse(args[0], {"header": true});
+printf(csv.stringify(rows, {"header": true}));
+printf(csv.stringify(rows, {"header": ["city"]}));
+printf(csv.stringify([{"a": 1}, {"b": 2}], {"header": true}));
+printf(csv.stringify([[1, 2]], {"separator": ";"}));
+`, "a,\"b,c\"\n1,\n"+people+"city\n\"London, UK\"\nParis\na\n1\n\n1;2\n", withArgs(people))
+}
+
+func TestCsvErrors(t *testing.T) {
+	expectError(t, `
+csv.parse("a,b
+c", {"header": true});
+`, "CSV row 2 has 1 fields but the header has 2.", 3, nil)
+	expectError(t, `
+csv.parse("a", {"nope": 1});
+`, "Unknown parse() option \"nope\".", 2, nil)
+	expectError(t, `
+csv.parse("a", {"separator": "ab"});
+`, "parse() option 'separator' must be a single character.", 2, nil)
+	expectError(t, `
+csv.stringify([1]);
+`, "CSV row 1 must be a list or a map.", 2, nil)
+	expectError(t, `
+csv.stringify([{"a": 1}]);
+`, "Rows that are maps need the 'header' option.", 2, nil)
+}
+
+func TestCsvRejectsUnusableSeparators(t *testing.T) {
+	expectError(t, `
+csv.stringify([[1, 2]], {"separator": args[0]});
+`, "stringify() option 'separator' can't be \"\\\"\".", 2, withArgs(`"`))
+	expectError(t, `
+csv.stringify([[1, 2]], {"separator": args[0]});
+`, "stringify() option 'separator' can't be \"\uFFFD\".", 2, withArgs("\uFFFD"))
+	expectError(t, `
+csv.parse("a", {"separator": "
+"});
+`, "parse() option 'separator' can't be \"\\n\".", 3, nil)
+}
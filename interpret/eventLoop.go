@@ -0,0 +1,140 @@
+package interpret
+
+import (
+	"sort"
+	"time"
+
+	"github.com/kljablon/golox/ast"
+)
+
+// eventLoop runs the callbacks of a task one at a time: async functions
+// resuming after an await, and timers started with setTimeout and
+// setInterval. The main script and every spawned task have their own, which
+// runs whenever top-level code awaits a promise and once more when the task
+// ends, until there is nothing left for it to do.
+type eventLoop struct {
+	queue  []func()
+	timers []*timer
+	lastID int
+}
+
+func newEventLoop() *eventLoop {
+	return &eventLoop{}
+}
+
+// timer calls callback with arguments once it is due, and again every
+// interval if it repeats. call is the call that started it.
+type timer struct {
+	id        int
+	call      ast.Token
+	due       time.Time
+	interval  time.Duration
+	repeat    bool
+	callback  LoxCallable
+	arguments []any
+}
+
+// schedule adds t to the timers, which are kept in the order they fire.
+func (l *eventLoop) schedule(t *timer) {
+	index := sort.Search(len(l.timers), func(i int) bool {
+		return l.timers[i].due.After(t.due)
+	})
+	l.timers = append(l.timers, nil)
+	copy(l.timers[index+1:], l.timers[index:])
+	l.timers[index] = t
+}
+
+func (l *eventLoop) cancel(id int) {
+	for index, t := range l.timers {
+		if t.id == id {
+			l.timers = append(l.timers[:index], l.timers[index+1:]...)
+			return
+		}
+	}
+}
+
+// run runs callbacks, waiting for timers when there are none ready, until
+// settled reports true or there is nothing left to run. A nil settled runs
+// until then. Other tasks can run while the loop waits for a timer.
+func (l *eventLoop) run(interpreter *Interpreter, settled func() bool) {
+	for settled == nil || !settled() {
+		if len(l.queue) > 0 {
+			callback := l.queue[0]
+			l.queue = l.queue[1:]
+			callback()
+			continue
+		}
+		if len(l.timers) == 0 {
+			return
+		}
+		next := l.timers[0]
+		if wait := next.due.Sub(interpreter.clock.Now()); wait > 0 {
+			interpreter.scheduler.block(func() {
+				interpreter.clock.Sleep(wait)
+			})
+			continue
+		}
+		l.timers = l.timers[1:]
+		if next.repeat {
+			next.due = next.due.Add(next.interval)
+			l.schedule(next)
+		}
+		l.fire(interpreter, next)
+	}
+}
+
+// fire calls the callback of t, reporting errors of a native callback at the
+// call that started the timer. A timer the callback starts in turn counts as
+// started there too.
+func (l *eventLoop) fire(interpreter *Interpreter, t *timer) {
+	interpreter.callSite = t.call
+	withNativeErrorAt(t.call, func() {
+		t.callback.call(interpreter, t.arguments)
+	})
+}
+
+// startTimer implements setTimeout(fn, ms, ...args) and
+// setInterval(fn, ms, ...args), returning the id of the new timer.
+func startTimer(repeat bool) NativeFn {
+	return func(interpreter *Interpreter, arguments []any) any {
+		callback := arguments[0].(LoxCallable)
+		milliseconds := arguments[1].(float64)
+		callbackArguments := arguments[2:]
+		minArity, maxArity := callback.arity()
+		if len(callbackArguments) < minArity || (maxArity >= 0 && len(callbackArguments) > maxArity) {
+			panic(NativeError{arityMessage(minArity, maxArity, len(callbackArguments))})
+		}
+		if repeat && milliseconds <= 0 {
+			panic(NativeError{"Interval must be more than 0 milliseconds."})
+		}
+		if milliseconds < 0 {
+			milliseconds = 0
+		}
+
+		loop := interpreter.loop
+		loop.lastID++
+		interval := toDuration(milliseconds)
+		loop.schedule(&timer{
+			id:        loop.lastID,
+			call:      interpreter.callSite,
+			due:       interpreter.clock.Now().Add(interval),
+			interval:  interval,
+			repeat:    repeat,
+			callback:  callback,
+			arguments: callbackArguments,
+		})
+		return float64(loop.lastID)
+	}
+}
+
+func clearTimer(interpreter *Interpreter, arguments []any) any {
+	interpreter.loop.cancel(int(arguments[0].(float64)))
+	return nil
+}
+
+func defineTimerNatives(i *Interpreter) {
+	i.DefineNative(NewNativeFunction("setTimeout", 2, -1, []ArgType{CALLABLE_ARG, NUMBER_ARG, ANY_ARG}, startTimer(false)))
+	i.DefineNative(NewNativeFunction("setInterval", 2, -1, []ArgType{CALLABLE_ARG, NUMBER_ARG, ANY_ARG}, startTimer(true)))
+	i.DefineNative(native("clearTimeout", []ArgType{NUMBER_ARG}, clearTimer))
+	i.DefineNative(native("clearInterval", []ArgType{NUMBER_ARG}, clearTimer))
+}
@@ -0,0 +1,118 @@
+package interpret_test
+
+import "testing"
+
+func TestAsyncOrdering(t *testing.T) {
+	expectOutput(t, `
+async fun work(name, ms) {
+  print "start " + name;
+  await promise.delay(ms);
+  print "end " + name;
+  return name;
+}
+var a = work("a", 30);
+var b = work("b", 10);
+print "sync";
+print await a;
+print await b;
+`, "start a\nstart b\nsync\nend b\nend a\na\nb\n", nil)
+}
+
+func TestPromiseAll(t *testing.T) {
+	expectOutput(t, `
+async fun double(n) {
+  await promise.delay(10 * n);
+  return n * 2;
+}
+var start = clock();
+print await promise.all([double(3), double(1), "plain", double(2)]);
+print clock() - start;
+print await promise.all([]);
+`, "[6, 2, \"plain\", 4]\n30\n[]\n", nil)
+}
+
+func TestPromiseRace(t *testing.T) {
+	expectOutput(t, `
+async fun after(name, ms) {
+  await promise.delay(ms);
+  print "done " + name;
+  return name;
+}
+print await promise.race([after("slow", 50), after("fast", 20)]);
+print await promise.race([after("late", 10), "now"]);
+`, "done fast\nfast\nnow\ndone late\ndone slow\n", nil)
+}
+
+func TestTimersAndClearInterval(t *testing.T) {
+	expectOutput(t, `
+var count = 0;
+var id;
+fun tick() {
+  count = count + 1;
+  print count;
+  if (count == 3) clearInterval(id);
+}
+fun say(message) { print message; }
+id = setInterval(tick, 10);
+setTimeout(say, 15, "timeout");
+var cancelled = setTimeout(say, 5, "cancelled");
+clearTimeout(cancelled);
+print "queued";
+`, "queued\n1\ntimeout\n2\n3\n", nil)
+}
+
+func TestAsyncErrorStopsScript(t *testing.T) {
+	expectError(t, `
+async fun fail() {
+  await promise.delay(5);
+  return math.sqrt("a");
+}
+fail();
+print "before";
+`, "Argument 1 to sqrt() must be a number.", 4, nil)
+}
+
+func TestAwaitPlainValues(t *testing.T) {
+	expectOutput(t, `
+print await 5;
+print await promise.resolve(2);
+`, "5\n2\n", nil)
+}
+
+func TestTimerErrors(t *testing.T) {
+	expectError(t, `
+setTimeout(1, 2);
+`, "Argument 1 to setTimeout() must be a function.", 2, nil)
+}
+
+func TestTimerErrorAtCallSite(t *testing.T) {
+	expectError(t, `
+var x = 1;
+setTimeout(math.sqrt, 1, "a");
+`, "Argument 1 to sqrt() must be a number.", 3, nil)
+}
+
+func TestTimerStartedBySpawnErrorAtSpawn(t *testing.T) {
+	expectError(t, `
+fun nothing() {}
+nothing();
+var task = spawn setTimeout(math.sqrt, 1, "a");
+task.wait();
+`, "Argument 1 to sqrt() must be a number.", 4, nil)
+}
+
+func TestTimerStartedByTimerErrorAtFirstCall(t *testing.T) {
+	expectError(t, `
+setTimeout(setTimeout, 1, math.sqrt, 1, "a");
+fun nothing() {}
+nothing();
+`, "Argument 1 to sqrt() must be a number.", 2, nil)
+}
+
+func TestTimerStartedByNativeCallbackErrorAtNative(t *testing.T) {
+	expectError(t, `
+fun nothing() {}
+reduce(setTimeout, [1], math.sqrt);
+nothing();
+`, "Expected 1 arguments but got 0.", 3, nil)
+}
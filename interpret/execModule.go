@@ -0,0 +1,118 @@
+package interpret
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// execResult is the record exec() returns.
+var execResult = &LoxRecord{"ExecResult", []string{"stdout", "stderr", "code"}}
+
+// SetExecAllowlist lets scripts run the given commands with exec(). A
+// command is matched exactly as the script names it, so "ls" and "/bin/ls"
+// are separate entries. exec() is disabled until this is called, and an
+// empty list disables it again. Commands run in the file root, if one is
+// set, and otherwise in the host's working directory.
+func (i *Interpreter) SetExecAllowlist(commands []string) {
+	if len(commands) == 0 {
+		i.execAllowlist = nil
+		return
+	}
+	i.execAllowlist = make(map[string]bool)
+	for _, command := range commands {
+		i.execAllowlist[command] = true
+	}
+}
+
+// execOptions reads the optional map of exec() options: "stdin", a string
+// fed to the command, and "timeout", in milliseconds.
+func execOptions(options *LoxMap) (stdin string, timeout time.Duration) {
+	for _, key := range options.keys {
+		value := options.valueOf(key)
+		switch key {
+		case "stdin":
+			text, ok := value.(string)
+			if !ok {
+				panic(NativeError{"exec() option 'stdin' must be a string."})
+			}
+			stdin = text
+		case "timeout":
+			milliseconds, ok := value.(float64)
+			if !ok || milliseconds <= 0 {
+				panic(NativeError{"exec() option 'timeout' must be a positive number."})
+			}
+			timeout = toDuration(milliseconds)
+		default:
+			panic(NativeError{"Unknown exec() option " + repr(key) + "."})
+		}
+	}
+	return stdin, timeout
+}
+
+// execWaitDelay is how long exec() waits for a command's output to close
+// after the command has exited or been killed. A process that inherited
+// the output and is still running can't hold exec() up for longer.
+const execWaitDelay = time.Second
+
+func defineExecNatives(i *Interpreter) {
+	i.DefineNative(NewNativeFunction("exec", 1, 3, []ArgType{STRING_ARG, LIST_ARG, MAP_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		command := arguments[0].(string)
+		if interpreter.execAllowlist == nil {
+			panic(NativeError{"exec() is disabled."})
+		}
+		if !interpreter.execAllowlist[command] {
+			panic(NativeError{"Command '" + command + "' is not allowed."})
+		}
+
+		var args []string
+		if len(arguments) >= 2 {
+			for _, arg := range arguments[1].(*LoxList).elements {
+				text, ok := arg.(string)
+				if !ok {
+					panic(NativeError{"Arguments to exec() must be strings."})
+				}
+				args = append(args, text)
+			}
+		}
+		var stdin string
+		var timeout time.Duration
+		if len(arguments) == 3 {
+			stdin, timeout = execOptions(arguments[2].(*LoxMap))
+		}
+
+		ctx := context.Background()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cmd := exec.CommandContext(ctx, command, args...)
+		stopProcessGroup(cmd)
+		cmd.WaitDelay = execWaitDelay
+		cmd.Dir = interpreter.fileRoot
+		cmd.Stdin = strings.NewReader(stdin)
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+
+		var err error
+		interpreter.scheduler.block(func() {
+			err = cmd.Run()
+		})
+		if ctx.Err() == context.DeadlineExceeded {
+			panic(NativeError{"Command '" + command + "' timed out."})
+		}
+		code := 0
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			code = exitErr.ExitCode()
+		} else if err != nil && !errors.Is(err, exec.ErrWaitDelay) {
+			panic(NativeError{"Can't run '" + command + "': " + err.Error() + "."})
+		}
+		return &LoxRecordInstance{execResult, []any{stdout.String(), stderr.String(), float64(code)}}
+	}))
+}
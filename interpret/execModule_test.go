@@ -0,0 +1,74 @@
+package interpret_test
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/kljablon/golox/interpret"
+)
+
+// allow lets scripts run the given commands, skipping the test if any of
+// them isn't installed.
+func allow(t *testing.T, commands ...string) func(i *interpret.Interpreter) {
+	t.Helper()
+	for _, command := range commands {
+		if _, err := exec.LookPath(command); err != nil {
+			t.Skip(command + " not found")
+		}
+	}
+	return func(i *interpret.Interpreter) {
+		i.SetExecAllowlist(commands)
+	}
+}
+
+func TestExec(t *testing.T) {
+	expectOutput(t, `
+var result = exec("sh", ["-c", "echo out; echo err >&2; exit 3"]);
+print result;
+print exec("cat", [], {"stdin": "fed in"}).stdout;
+print exec("sh", ["-c", "exit 0"]).code;
+`, "ExecResult(stdout: \"out\\n\", stderr: \"err\\n\", code: 3)\nfed in\n0\n", allow(t, "sh", "cat"))
+}
+
+func TestExecTimeout(t *testing.T) {
+	expectError(t, `
+exec("sleep", ["5"], {"timeout": 50});
+`, "Command 'sleep' timed out.", 2, allow(t, "sleep"))
+}
+
+// The shell stays the parent of sleep here, so the timeout has to stop the
+// whole process group rather than just the shell.
+func TestExecTimeoutStopsChildren(t *testing.T) {
+	start := time.Now()
+	expectError(t, `
+exec("sh", ["-c", "sleep 5; echo done"], {"timeout": 50});
+`, "Command 'sh' timed out.", 2, allow(t, "sh", "sleep"))
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("exec() took %v to time out", elapsed)
+	}
+}
+
+// A background child keeps the command's output open after the command
+// exits; exec() returns what was written once the wait delay runs out.
+func TestExecDoesNotWaitForBackgroundChildren(t *testing.T) {
+	start := time.Now()
+	expectOutput(t, `
+print exec("sh", ["-c", "echo out; sleep 5 &"]);
+`, "ExecResult(stdout: \"out\\n\", stderr: \"\", code: 0)\n", allow(t, "sh", "sleep"))
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("exec() took %v to return", elapsed)
+	}
+}
+
+func TestExecAllowlist(t *testing.T) {
+	expectError(t, `
+exec("sh");
+`, "exec() is disabled.", 2, nil)
+	expectError(t, `
+exec("ls");
+`, "Command 'ls' is not allowed.", 2, allow(t, "sh"))
+	expectError(t, `
+exec("sh", ["-c", "true"], {"retries": 1});
+`, "Unknown exec() option \"retries\".", 2, allow(t, "sh"))
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package interpret
+
+import "os/exec"
+
+// stopProcessGroup leaves cmd as it is on systems without process groups;
+// cancelling it kills only the command itself.
+func stopProcessGroup(cmd *exec.Cmd) {}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package interpret
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// stopProcessGroup runs cmd in a process group of its own and makes
+// cancelling it kill the whole group, so children the command started,
+// such as those of a shell, don't outlive it.
+func stopProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
@@ -0,0 +1,248 @@
+package interpret
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// SetFileRoot confines the file natives to the directory root. Script paths
+// are then resolved relative to root, "/" included, and paths that would
+// leave it through ".." or a symbolic link are rejected. An empty root lifts
+// the restriction.
+func (i *Interpreter) SetFileRoot(root string) error {
+	if root == "" {
+		i.fileRoot = ""
+		return nil
+	}
+	absolute, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	resolved, err := filepath.EvalSymlinks(absolute)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return errors.New(root + " is not a directory")
+	}
+	i.fileRoot = resolved
+	return nil
+}
+
+// resolvePath maps a path given by a script to a host path, enforcing the
+// file root if one is set.
+func (i *Interpreter) resolvePath(path string) string {
+	if i.fileRoot == "" {
+		return path
+	}
+	full := filepath.Join(i.fileRoot, filepath.Clean(string(filepath.Separator)+path))
+
+	// The lexical join can't climb above the root, but a symbolic link
+	// inside it still could. Check where the deepest existing part of the
+	// path really leads.
+	existing := full
+	for {
+		if _, err := os.Lstat(existing); err == nil || existing == i.fileRoot {
+			break
+		}
+		existing = filepath.Dir(existing)
+	}
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil || !withinRoot(i.fileRoot, resolved) {
+		panic(NativeError{"Path '" + path + "' is outside the file root."})
+	}
+	return full
+}
+
+func withinRoot(root string, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// fileError reports a failed file operation using the script's own path, so
+// the host location of the file root isn't leaked into error messages.
+func fileError(action string, path string, err error) NativeError {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		err = pathErr.Err
+	}
+	return NativeError{"Can't " + action + " '" + path + "': " + err.Error() + "."}
+}
+
+// readFile reads a whole file without holding the interpreter lock, so
+// other tasks can run meanwhile.
+func readFile(interpreter *Interpreter, path string) (data []byte, err error) {
+	resolved := interpreter.resolvePath(path)
+	interpreter.scheduler.block(func() {
+		data, err = os.ReadFile(resolved)
+	})
+	return data, err
+}
+
+// writeFile writes data to a file opened with flag, without holding the
+// interpreter lock.
+func writeFile(interpreter *Interpreter, path string, data []byte, flag int) {
+	resolved := interpreter.resolvePath(path)
+	var err error
+	interpreter.scheduler.block(func() {
+		var file *os.File
+		file, err = os.OpenFile(resolved, flag, 0o644)
+		if err != nil {
+			return
+		}
+		_, err = file.Write(data)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	})
+	if err != nil {
+		panic(fileError("write", path, err))
+	}
+}
+
+func defineFileNatives(i *Interpreter) {
+	i.DefineNative(native("readFile", []ArgType{STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		path := arguments[0].(string)
+		data, err := readFile(interpreter, path)
+		if err != nil {
+			panic(fileError("read", path, err))
+		}
+		return string(data)
+	}))
+	i.DefineNative(native("readBytes", []ArgType{STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		path := arguments[0].(string)
+		data, err := readFile(interpreter, path)
+		if err != nil {
+			panic(fileError("read", path, err))
+		}
+		return NewLoxBytes(data)
+	}))
+	i.DefineNative(native("writeFile", []ArgType{STRING_ARG, DATA_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		writeFile(interpreter, arguments[0].(string), toData(arguments[1]), os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+		return nil
+	}))
+	i.DefineNative(native("appendFile", []ArgType{STRING_ARG, DATA_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		writeFile(interpreter, arguments[0].(string), toData(arguments[1]), os.O_WRONLY|os.O_CREATE|os.O_APPEND)
+		return nil
+	}))
+	i.DefineNative(native("listDir", []ArgType{STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		path := arguments[0].(string)
+		resolved := interpreter.resolvePath(path)
+		var entries []os.DirEntry
+		var err error
+		interpreter.scheduler.block(func() {
+			entries, err = os.ReadDir(resolved)
+		})
+		if err != nil {
+			panic(fileError("list", path, err))
+		}
+		names := make([]any, len(entries))
+		for index, entry := range entries {
+			names[index] = entry.Name()
+		}
+		return NewLoxList(names)
+	}))
+	i.DefineNative(native("exists", []ArgType{STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		resolved := interpreter.resolvePath(arguments[0].(string))
+		var err error
+		interpreter.scheduler.block(func() {
+			_, err = os.Stat(resolved)
+		})
+		return err == nil
+	}))
+	i.DefineNative(native("remove", []ArgType{STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		path := arguments[0].(string)
+		resolved := interpreter.resolvePath(path)
+		var err error
+		interpreter.scheduler.block(func() {
+			err = os.Remove(resolved)
+		})
+		if err != nil {
+			panic(fileError("remove", path, err))
+		}
+		return nil
+	}))
+	i.DefineNative(native("lines", []ArgType{STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		path := arguments[0].(string)
+		resolved := interpreter.resolvePath(path)
+		var file *os.File
+		var err error
+		interpreter.scheduler.block(func() {
+			file, err = os.Open(resolved)
+		})
+		if err != nil {
+			panic(fileError("read", path, err))
+		}
+		return &LoxLines{scheduler: interpreter.scheduler, path: path, file: file, reader: bufio.NewReader(file)}
+	}))
+}
+
+// LoxLines streams the lines of a file, without their line endings, one at
+// a time. It can only be iterated once; the file is closed when the last
+// line has been read, or when a loop over it stops early. Lines are read without holding the interpreter lock,
+// and lock keeps two tasks iterating the same file from reading at once.
+type LoxLines struct {
+	scheduler *scheduler
+	lock      sync.Mutex
+	path      string
+	file      *os.File
+	reader    *bufio.Reader
+}
+
+func (l *LoxLines) iterator() LoxIterator {
+	return l
+}
+
+func (l *LoxLines) next() (any, bool) {
+	if l.file == nil {
+		return nil, false
+	}
+	var line string
+	var err error
+	l.scheduler.block(func() {
+		l.lock.Lock()
+		defer l.lock.Unlock()
+		line, err = l.reader.ReadString('\n')
+	})
+	if l.file == nil {
+		// Another task read the last line while this one waited.
+		return nil, false
+	}
+	if err != nil {
+		l.release()
+		if err != io.EOF {
+			panic(fileError("read", l.path, err))
+		}
+		if line == "" {
+			return nil, false
+		}
+	}
+	line = strings.TrimSuffix(line, "\n")
+	return strings.TrimSuffix(line, "\r"), true
+}
+
+// release closes the file if it is still open. lock waits for a read
+// another task has in progress.
+func (l *LoxLines) release() {
+	if l.file == nil {
+		return
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.file.Close()
+	l.file = nil
+}
+
+func (l *LoxLines) toString() string {
+	return "<lines " + l.path + ">"
+}
@@ -0,0 +1,182 @@
+package interpret_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/kljablon/golox/interpret"
+)
+
+// sandbox makes a file root holding inside.txt, next to a directory outside
+// it holding secret.txt, and returns a setup confining scripts to the root.
+func sandbox(t *testing.T) (string, func(i *interpret.Interpreter)) {
+	t.Helper()
+	dir := t.TempDir()
+	root := filepath.Join(dir, "root")
+	outside := filepath.Join(dir, "outside")
+	for _, path := range []string{root, outside} {
+		if err := os.Mkdir(path, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "inside.txt"), []byte("inside"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir, func(i *interpret.Interpreter) {
+		if err := i.SetFileRoot(root); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFileRootReadsInside(t *testing.T) {
+	_, setup := sandbox(t)
+	expectOutput(t, `
+print readFile("inside.txt");
+print readFile("/inside.txt");
+print exists("../outside/secret.txt");
+`, "inside\ninside\nfalse\n", setup)
+}
+
+func TestFileRootDotDot(t *testing.T) {
+	_, setup := sandbox(t)
+	expectError(t, `
+print readFile("../outside/secret.txt");
+`, "Can't read '../outside/secret.txt': no such file or directory.", 2, setup)
+}
+
+func TestFileRootDotDotWrite(t *testing.T) {
+	dir, setup := sandbox(t)
+	expectOutput(t, `
+writeFile("../../escaped.txt", "x");
+print exists("escaped.txt");
+`, "true\n", setup)
+	if _, err := os.Stat(filepath.Join(dir, "escaped.txt")); err == nil {
+		t.Error("writeFile escaped the file root")
+	}
+}
+
+func TestFileRootSymlink(t *testing.T) {
+	dir, setup := sandbox(t)
+	root := filepath.Join(dir, "root")
+	if err := os.Symlink(filepath.Join(dir, "outside"), filepath.Join(root, "link")); err != nil {
+		t.Skip("symbolic links unavailable:", err)
+	}
+	if err := os.Symlink(filepath.Join(dir, "outside", "secret.txt"), filepath.Join(root, "secret.txt")); err != nil {
+		t.Fatal(err)
+	}
+	expectError(t, `
+print readFile("link/secret.txt");
+`, "Path 'link/secret.txt' is outside the file root.", 2, setup)
+	expectError(t, `
+print readFile("secret.txt");
+`, "Path 'secret.txt' is outside the file root.", 2, setup)
+	expectError(t, `
+writeFile("link/new.txt", "x");
+`, "Path 'link/new.txt' is outside the file root.", 2, setup)
+	if _, err := os.Stat(filepath.Join(dir, "outside", "new.txt")); err == nil {
+		t.Error("writeFile followed a link out of the file root")
+	}
+}
+
+func TestFileNatives(t *testing.T) {
+	dir, setup := sandbox(t)
+	if err := os.WriteFile(filepath.Join(dir, "root", "crlf.txt"), []byte("one\r\ntwo\n\nthree"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	expectOutput(t, `
+writeFile("a.txt", "first");
+appendFile("a.txt", " second");
+print readFile("a.txt");
+for (line in lines("crlf.txt")) print "[" + line + "]";
+print listDir("/");
+print exists("a.txt");
+remove("a.txt");
+print exists("a.txt");
+`, "first second\n[one]\n[two]\n[]\n[three]\n[\"a.txt\", \"crlf.txt\", \"inside.txt\"]\ntrue\nfalse\n", setup)
+}
+
+func TestFileErrorsUseScriptPath(t *testing.T) {
+	_, setup := sandbox(t)
+	expectError(t, `
+readFile("missing.txt");
+`, "Can't read 'missing.txt': no such file or directory.", 2, setup)
+	expectError(t, `
+remove("missing.txt");
+`, "Can't remove 'missing.txt': no such file or directory.", 2, setup)
+	expectError(t, `
+for (line in lines("missing.txt")) print line;
+`, "Can't read 'missing.txt': no such file or directory.", 2, setup)
+}
+
+// openFiles counts the files the process has open, skipping the test where
+// that can't be seen.
+func openFiles(t *testing.T) int {
+	t.Helper()
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Skip("can't list open files")
+	}
+	return len(entries)
+}
+
+func TestLinesClosedWhenLoopStopsEarly(t *testing.T) {
+	_, setup := sandbox(t)
+	expectOutput(t, `
+writeFile("three.txt", "a
+b
+c
+");
+fun first(path) {
+  for (line in lines(path)) return line;
+}
+var before = openFiles();
+print first("three.txt");
+print any(lines("three.txt"));
+print zip(lines("three.txt"), [1]);
+print [line for line in lines("three.txt") if line == "a"];
+print openFiles() - before;
+`, "a\ntrue\n[(\"a\", 1)]\n[\"a\"]\n0\n", func(i *interpret.Interpreter) {
+		setup(i)
+		i.DefineNative(interpret.NewNativeFunction("openFiles", 0, 0, nil, func(interpreter *interpret.Interpreter, arguments []any) any {
+			return float64(openFiles(t))
+		}))
+	})
+}
+
+func TestLinesClosedOnError(t *testing.T) {
+	_, setup := sandbox(t)
+	before := openFiles(t)
+	expectError(t, `
+writeFile("two.txt", "a
+b
+");
+for (line in lines("two.txt")) math.sqrt(line);
+`, "Argument 1 to sqrt() must be a number.", 5, setup)
+	if after := openFiles(t); after != before {
+		t.Errorf("%d files open after the script, want %d", after, before)
+	}
+}
+
+// Opening a named pipe to write waits for a reader, which here is a task
+// that can only start once the writing script lets it run.
+func TestWriteFileLetsOtherTasksRun(t *testing.T) {
+	dir, setup := sandbox(t)
+	if err := exec.Command("mkfifo", filepath.Join(dir, "root", "pipe")).Run(); err != nil {
+		t.Skip("can't make a named pipe")
+	}
+	expectOutput(t, `
+fun reader() { return readFile("pipe"); }
+var task = spawn reader();
+print exists("pipe");
+writeFile("pipe", "through the pipe");
+print task.wait();
+remove("pipe");
+print exists("pipe");
+`, "true\nthrough the pipe\nfalse\n", setup)
+}
@@ -0,0 +1,238 @@
+package interpret
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// format implements the format() and printf() natives. Placeholders are
+// written in braces and may name the value they print:
+//
+//	{}        the next argument
+//	{1}       the argument at that position
+//	{name}    a key of the map passed as the last argument
+//
+// followed by an optional spec after a colon, [[fill]align][sign][0][width]
+// [.precision][type], where align is one of < > ^, sign is + or a space, and
+// type is one of s d x o b f e g %. Literal braces are written {{ and }}.
+func format(template string, arguments []any) string {
+	var out strings.Builder
+	next := 0
+	for i := 0; i < len(template); i++ {
+		c := template[i]
+		switch {
+		case c == '{' && strings.HasPrefix(template[i:], "{{"):
+			out.WriteByte('{')
+			i++
+		case c == '{':
+			end := strings.IndexByte(template[i:], '}')
+			if end < 0 {
+				panic(NativeError{"Unmatched '{' in format string."})
+			}
+			field := template[i+1 : i+end]
+			i += end
+
+			name, spec, _ := strings.Cut(field, ":")
+			var value any
+			if name == "" {
+				value = positionalArgument(arguments, next)
+				next++
+			} else if position, err := strconv.Atoi(name); err == nil {
+				value = positionalArgument(arguments, position)
+			} else {
+				value = namedArgument(arguments, name)
+			}
+			out.WriteString(formatValue(value, parseFormatSpec(spec)))
+		case c == '}' && strings.HasPrefix(template[i:], "}}"):
+			out.WriteByte('}')
+			i++
+		case c == '}':
+			panic(NativeError{"Single '}' in format string."})
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return out.String()
+}
+
+func positionalArgument(arguments []any, position int) any {
+	if position < 0 || position >= len(arguments) {
+		panic(NativeError{fmt.Sprintf("Format string refers to argument %d but only %d were given.", position, len(arguments))})
+	}
+	return arguments[position]
+}
+
+func namedArgument(arguments []any, name string) any {
+	if len(arguments) > 0 {
+		if named, ok := arguments[len(arguments)-1].(*LoxMap); ok {
+			if value, ok := named.values[name]; ok {
+				return value
+			}
+		}
+	}
+	panic(NativeError{"No value for '{" + name + "}' in format arguments."})
+}
+
+type formatSpec struct {
+	fill      rune
+	align     byte
+	sign      byte
+	zero      bool
+	width     int
+	precision int
+	verb      byte
+}
+
+func parseFormatSpec(spec string) formatSpec {
+	result := formatSpec{fill: ' ', precision: -1}
+	rest := spec
+
+	if r, size := utf8.DecodeRuneInString(rest); size > 0 && size < len(rest) && strings.ContainsRune("<>^", rune(rest[size])) {
+		result.fill = r
+		result.align = rest[size]
+		rest = rest[size+1:]
+	} else if rest != "" && strings.ContainsRune("<>^", rune(rest[0])) {
+		result.align = rest[0]
+		rest = rest[1:]
+	}
+	if rest != "" && (rest[0] == '+' || rest[0] == ' ') {
+		result.sign = rest[0]
+		rest = rest[1:]
+	}
+	if rest != "" && rest[0] == '0' {
+		result.zero = true
+		rest = rest[1:]
+	}
+	digits := leadingDigits(rest)
+	if digits != "" {
+		result.width = specNumber(digits, "Width", spec)
+		rest = rest[len(digits):]
+	}
+	if rest != "" && rest[0] == '.' {
+		digits = leadingDigits(rest[1:])
+		if digits == "" {
+			panic(NativeError{"Missing precision in format spec '" + spec + "'."})
+		}
+		result.precision = specNumber(digits, "Precision", spec)
+		rest = rest[1+len(digits):]
+	}
+	if len(rest) == 1 && strings.ContainsRune("sdxobfeg%", rune(rest[0])) {
+		result.verb = rest[0]
+		rest = ""
+	}
+	if rest != "" {
+		panic(NativeError{"Invalid format spec '" + spec + "'."})
+	}
+	return result
+}
+
+// maxSpecNumber bounds the width and precision of a format spec.
+const maxSpecNumber = 1000
+
+// specNumber parses the width or precision of spec.
+func specNumber(digits string, what string, spec string) int {
+	number, err := strconv.Atoi(digits)
+	if err != nil || number > maxSpecNumber {
+		panic(NativeError{fmt.Sprintf("%s in format spec '%s' can't be more than %d.", what, spec, maxSpecNumber)})
+	}
+	return number
+}
+
+func leadingDigits(s string) string {
+	end := 0
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	return s[:end]
+}
+
+func formatValue(value any, spec formatSpec) string {
+	number, isNumber := value.(float64)
+	var text string
+
+	switch spec.verb {
+	case 'd', 'x', 'o', 'b':
+		if !isNumber || number != math.Trunc(number) || math.IsInf(number, 0) {
+			panic(NativeError{"Format type '" + string(spec.verb) + "' requires an integer."})
+		}
+		base := map[byte]int{'d': 10, 'x': 16, 'o': 8, 'b': 2}[spec.verb]
+		// Integers beyond int64 are still exact in a float64, so they are
+		// formatted through big.Int rather than truncated.
+		whole, _ := big.NewFloat(math.Abs(number)).Int(nil)
+		text = whole.Text(base)
+	case 'f', 'e', 'g', '%':
+		if !isNumber {
+			panic(NativeError{"Format type '" + string(spec.verb) + "' requires a number."})
+		}
+		precision := spec.precision
+		if precision < 0 && spec.verb != 'g' {
+			precision = 6
+		}
+		if spec.verb == '%' {
+			text = strconv.FormatFloat(math.Abs(number)*100, 'f', precision, 64) + "%"
+		} else {
+			text = strconv.FormatFloat(math.Abs(number), spec.verb, precision, 64)
+		}
+	case 's':
+		isNumber = false
+		text = truncate(stringify(value), spec.precision)
+	default:
+		if isNumber && spec.precision >= 0 {
+			text = strconv.FormatFloat(math.Abs(number), 'f', spec.precision, 64)
+		} else if isNumber {
+			text = stringify(math.Abs(number))
+		} else {
+			text = truncate(stringify(value), spec.precision)
+		}
+	}
+
+	sign := ""
+	if isNumber {
+		if math.Signbit(number) && !math.IsNaN(number) {
+			sign = "-"
+		} else if spec.sign != 0 {
+			sign = string(spec.sign)
+		}
+	}
+	return pad(sign, text, spec, isNumber)
+}
+
+func truncate(text string, precision int) string {
+	if precision >= 0 && utf8.RuneCountInString(text) > precision {
+		return string([]rune(text)[:precision])
+	}
+	return text
+}
+
+// pad applies the width and alignment of spec. Numbers align right by
+// default, and zero padding goes between the sign and the digits.
+func pad(sign string, text string, spec formatSpec, isNumber bool) string {
+	missing := spec.width - utf8.RuneCountInString(sign+text)
+	if missing <= 0 {
+		return sign + text
+	}
+	if isNumber && spec.zero && spec.align == 0 {
+		return sign + strings.Repeat("0", missing) + text
+	}
+
+	fill := string(spec.fill)
+	align := spec.align
+	if align == 0 {
+		align = '<'
+		if isNumber {
+			align = '>'
+		}
+	}
+	switch align {
+	case '>':
+		return strings.Repeat(fill, missing) + sign + text
+	case '^':
+		left := missing / 2
+		return strings.Repeat(fill, left) + sign + text + strings.Repeat(fill, missing-left)
+	}
+	return sign + text + strings.Repeat(fill, missing)
+}
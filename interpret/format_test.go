@@ -0,0 +1,54 @@
+package interpret_test
+
+import "testing"
+
+func TestFormatPlaceholders(t *testing.T) {
+	expectOutput(t, `
+print format("{} + {} = {}", 1, 2, 3);
+print format("{1}{0}{1}", "a", "b");
+print format("{name} is {age}", {"name": "Ada", "age": 36});
+print format("{{}} {:s}", "x");
+printf("{}-{}", "a", "b");
+`, "1 + 2 = 3\nbab\nAda is 36\n{} x\na-b", nil)
+}
+
+func TestFormatSpecs(t *testing.T) {
+	expectOutput(t, `
+print format("[{:>5}] [{:<5}] [{:^5}] [{:*^7}]", "ab", "ab", "ab", "ab");
+print format("{:05d} {:+d} {:x} {:o} {:b}", 42, 7, 255, 8, 5);
+print format("{:.2f} {:.3e} {:g} {:.1%}", 3.14159, 1234.5, 0.5, 0.256);
+print format("{:08.3f}", -3.14159);
+`, "[   ab] [ab   ] [ ab  ] [**ab***]\n00042 +7 ff 10 101\n3.14 1.234e+03 0.5 25.6%\n-003.142\n", nil)
+}
+
+func TestFormatErrors(t *testing.T) {
+	expectError(t, `
+format("{} {}", 1);
+`, "Format string refers to argument 1 but only 1 were given.", 2, nil)
+	expectError(t, `
+format("{");
+`, "Unmatched '{' in format string.", 2, nil)
+	expectError(t, `
+format("{:d}", "a");
+`, "Format type 'd' requires an integer.", 2, nil)
+	expectError(t, `
+format("{:q}", 1);
+`, "Invalid format spec 'q'.", 2, nil)
+	expectError(t, `
+format("{x}", 1);
+`, "No value for '{x}' in format arguments.", 2, nil)
+}
+
+func TestFormatLimits(t *testing.T) {
+	expectOutput(t, `
+print format("{:d}", math.pow(2, 70));
+print format("{:x}", math.pow(2, 64));
+print format("{:1000}|", "").length;
+`, "1180591620717411303424\n10000000000000000\n1001\n", nil)
+	expectError(t, `
+format("{:1001}", 1);
+`, "Width in format spec '1001' can't be more than 1000.", 2, nil)
+	expectError(t, `
+format("{:.99999999999999999999f}", 1);
+`, "Precision in format spec '.99999999999999999999f' can't be more than 1000.", 2, nil)
+}
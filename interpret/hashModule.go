@@ -0,0 +1,67 @@
+package interpret
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+)
+
+// hashAlgorithms are the digests available to scripts, by name.
+var hashAlgorithms = map[string]func() hash.Hash{
+	"sha256": sha256.New,
+	"sha1":   sha1.New,
+	"md5":    md5.New,
+}
+
+// digest hashes data and returns the digest as a lowercase hex string.
+func digest(newHash func() hash.Hash, data []byte) string {
+	h := newHash()
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func defineHashNatives(i *Interpreter) {
+	for name, newHash := range hashAlgorithms {
+		newHash := newHash
+		i.DefineNative(native(name, []ArgType{DATA_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			return digest(newHash, toData(arguments[0]))
+		}))
+	}
+	i.DefineNative(NewNativeFunction("hmac", 2, 3, []ArgType{DATA_ARG, DATA_ARG, STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		algorithm := "sha256"
+		if len(arguments) == 3 {
+			algorithm = arguments[2].(string)
+		}
+		newHash, ok := hashAlgorithms[algorithm]
+		if !ok {
+			panic(NativeError{"Unknown hash algorithm '" + algorithm + "'."})
+		}
+		key := toData(arguments[0])
+		return digest(func() hash.Hash { return hmac.New(newHash, key) }, toData(arguments[1]))
+	}))
+
+	i.DefineNative(native("base64Encode", []ArgType{DATA_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		return base64.StdEncoding.EncodeToString(toData(arguments[0]))
+	}))
+	i.DefineNative(native("base64Decode", []ArgType{STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		data, err := base64.StdEncoding.DecodeString(arguments[0].(string))
+		if err != nil {
+			panic(NativeError{"Invalid base64: " + err.Error() + "."})
+		}
+		return NewLoxBytes(data)
+	}))
+	i.DefineNative(native("hexEncode", []ArgType{DATA_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		return hex.EncodeToString(toData(arguments[0]))
+	}))
+	i.DefineNative(native("hexDecode", []ArgType{STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		data, err := hex.DecodeString(arguments[0].(string))
+		if err != nil {
+			panic(NativeError{"Invalid hex: " + err.Error() + "."})
+		}
+		return NewLoxBytes(data)
+	}))
+}
@@ -0,0 +1,46 @@
+package interpret_test
+
+import "testing"
+
+func TestHashes(t *testing.T) {
+	expectOutput(t, `
+print sha256("abc");
+print sha1("abc");
+print md5("abc");
+print hmac("key", "message");
+print hmac("key", "message", "sha1");
+`, `ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad
+a9993e364706816aba3e25717850c26c9cd0d89d
+900150983cd24fb0d6963f7d28e17f72
+6e9ef29b75fffc5b7abae527d58fdadb2fe42e7219011976917343065f58ed4a
+2088df74d5f2146b48146caf4965377e9d0be3a4
+`, nil)
+}
+
+func TestEncodingsAndBytes(t *testing.T) {
+	expectOutput(t, `
+print base64Encode("héllo");
+var b = base64Decode("aMOpbGxv");
+print b;
+print b.length;
+print b.text();
+print b == hexDecode("68c3a96c6c6f");
+print hexEncode(b);
+print sha256(b) == sha256("héllo");
+`, "aMOpbGxv\nb\"h\\xc3\\xa9llo\"\n6\nhéllo\ntrue\n68c3a96c6c6f\ntrue\n", nil)
+}
+
+func TestHashErrors(t *testing.T) {
+	expectError(t, `
+base64Decode("!!");
+`, "Invalid base64: illegal base64 data at input byte 0.", 2, nil)
+	expectError(t, `
+hexDecode("zz");
+`, "Invalid hex: encoding/hex: invalid byte: U+007A 'z'.", 2, nil)
+	expectError(t, `
+hmac("k", "m", "sha512");
+`, "Unknown hash algorithm 'sha512'.", 2, nil)
+	expectError(t, `
+sha256(1);
+`, "Argument 1 to sha256() must be a string or bytes.", 2, nil)
+}
@@ -1,8 +1,12 @@
 package interpret
 
 import (
+	"bufio"
 	"fmt"
 	"log"
+	"math"
+	"math/rand"
+	"strconv"
 
 	"github.com/kljablon/golox/ast"
 	"github.com/kljablon/golox/utils"
@@ -13,29 +17,80 @@ type Return struct {
 }
 
 type Interpreter struct {
-	globals     Environment
-	environment Environment
-	locals      map[ast.Expr]int
+	globals       Environment
+	environment   Environment
+	locals        map[ast.Expr]int
+	fileRoot      string
+	clock         Clock
+	random        *rand.Rand
+	input         *bufio.Reader
+	execAllowlist map[string]bool
+
+	// scheduler is shared by the tasks of a script, and steps counts the
+	// statements the current task has executed.
+	scheduler *scheduler
+	steps     int
+
+	// loop runs the callbacks of the current task, and coroutine is set
+	// while running the body of an async function.
+	loop      *eventLoop
+	coroutine *coroutine
+
+	// callSite is the paren of the call being made, for natives that
+	// report errors after they return, such as the callbacks of timers.
+	// Calls that don't come from a call expression, such as those of spawn
+	// and of timers, set it to the call they run on behalf of.
+	callSite ast.Token
 }
 
 func NewInterpreter() Interpreter {
 	globals := NewEnvironment()
 
-	// add native functions to global env
-	globals.define("clock", ClockFunc{})
-
 	locals := make(map[ast.Expr]int)
-	return Interpreter{
+	interpreter := Interpreter{
 		globals:     globals,
 		environment: globals,
 		locals:      locals,
+		clock:       systemClock{},
+		scheduler:   newScheduler(),
+		loop:        newEventLoop(),
 	}
+
+	// add native functions to global env
+	defineBuiltins(&interpreter)
+	return interpreter
 }
 
-func (i *Interpreter) Interpret(statements []ast.Stmt) {
+// Interpret executes the statements, stopping at the first runtime error,
+// which is returned to the caller. A call to exit() is returned as an
+// ExitError. Both also end the script when they happen in a spawned task.
+// Tasks still running when the statements finish are stopped.
+func (i *Interpreter) Interpret(statements []ast.Stmt) (err error) {
+	i.scheduler = i.scheduler.restart()
+	i.loop = newEventLoop()
+	defer func() {
+		i.scheduler.stop()
+		i.scheduler.lock.Unlock()
+	}()
+	defer func() {
+		if r := recover(); r != nil {
+			if runtimeErr, ok := r.(utils.RuntimeError); ok {
+				err = runtimeErr
+				return
+			}
+			if exitErr, ok := r.(ExitError); ok {
+				err = exitErr
+				return
+			}
+			panic(r)
+		}
+	}()
 	for _, statement := range statements {
 		i.execute(statement)
 	}
+	i.loop.run(i, nil)
+	i.scheduler.checkStopped()
+	return nil
 }
 
 func (i *Interpreter) VisitExpr_Binary(e ast.Expr_Binary) any {
@@ -80,10 +135,8 @@ func (i *Interpreter) VisitExpr_Binary(e ast.Expr_Binary) any {
 		i.checkNumberOperands(e.Operator, left, right)
 		return utils.CastToFloat(left) <= utils.CastToFloat(right)
 	case ast.BANG_EQUAL:
-		i.checkNumberOperands(e.Operator, left, right)
 		return !utils.IsEqual(left, right)
 	case ast.EQUAL_EQUAL:
-		i.checkNumberOperands(e.Operator, left, right)
 		return utils.IsEqual(left, right)
 	default:
 		return nil
@@ -93,19 +146,240 @@ func (i *Interpreter) VisitExpr_Binary(e ast.Expr_Binary) any {
 
 func (i *Interpreter) VisitExpr_Call(e ast.Expr_Call) any {
 	callee := i.evaluate(e.Callee)
-	arguments := []any{}
-	for _, argument := range e.Arguments {
-		arguments = append(arguments, argument)
+	arguments := i.evaluateElements(e.Arguments)
+
+	defer func() {
+		if r := recover(); r != nil {
+			if err, ok := r.(NativeError); ok {
+				panic(utils.NewRuntimeError(e.Paren, err.Message))
+			}
+			panic(r)
+		}
+	}()
+
+	function := checkCallable(e.Paren, callee, arguments)
+	caller := i.callSite
+	i.callSite = e.Paren
+	result := function.call(i, arguments)
+	i.callSite = caller
+	return result
+}
+
+// checkCallable reports a runtime error at paren unless callee can be called
+// with arguments.
+func checkCallable(paren ast.Token, callee any, arguments []any) LoxCallable {
+	function, ok := callee.(LoxCallable)
+	if !ok {
+		panic(utils.NewRuntimeError(paren, "Can only call functions and classes."))
+	}
+	minArity, maxArity := function.arity()
+	if len(arguments) < minArity || (maxArity >= 0 && len(arguments) > maxArity) {
+		panic(utils.NewRuntimeError(paren, arityMessage(minArity, maxArity, len(arguments))))
+	}
+	return function
+}
+
+// VisitExpr_Spawn evaluates the function and arguments of the call, then
+// makes the call on a new task and returns its handle at once.
+func (i *Interpreter) VisitExpr_Spawn(e ast.Expr_Spawn) any {
+	callee := i.evaluate(e.Call.Callee)
+	arguments := i.evaluateElements(e.Call.Arguments)
+	function := checkCallable(e.Call.Paren, callee, arguments)
+	return i.scheduler.spawn(i, e.Call.Paren, function, arguments)
+}
+
+func arityMessage(minArity int, maxArity int, got int) string {
+	switch {
+	case minArity == maxArity:
+		return fmt.Sprintf("Expected %d arguments but got %d.", minArity, got)
+	case maxArity < 0:
+		return fmt.Sprintf("Expected at least %d arguments but got %d.", minArity, got)
+	}
+	return fmt.Sprintf("Expected %d to %d arguments but got %d.", minArity, maxArity, got)
+}
+
+func (i *Interpreter) VisitExpr_List(e ast.Expr_List) any {
+	return NewLoxList(i.evaluateElements(e.Elements))
+}
+
+// evaluateElements evaluates list elements or call arguments, expanding any
+// spread expressions among them in place.
+func (i *Interpreter) evaluateElements(exprs []ast.Expr) []any {
+	values := []any{}
+	for _, expr := range exprs {
+		spread, ok := expr.(*ast.Expr_Spread)
+		if !ok {
+			values = append(values, i.evaluate(expr))
+			continue
+		}
+		iterator, ok := iteratorFor(i.evaluate(spread.Expression))
+		if !ok {
+			panic(utils.NewRuntimeError(spread.Ellipsis, "Can only spread iterable values."))
+		}
+		for {
+			value, ok := nextAt(spread.Ellipsis, iterator)
+			if !ok {
+				break
+			}
+			values = append(values, value)
+		}
 	}
+	return values
+}
+
+func (i *Interpreter) VisitExpr_Spread(e ast.Expr_Spread) any {
+	panic(utils.NewRuntimeError(e.Ellipsis, "Spread is only allowed in list literals and call arguments."))
+}
 
-	if function, ok := callee.(LoxFunction); ok {
-		if len(arguments) != function.arity() {
-			log.Fatalf("Expected %d arguments but got %d.", function.arity(), len(arguments))
+// comprehend runs the for/if clause shared by list and map comprehensions,
+// calling produce in the loop variable's environment for every element that
+// passes the condition.
+func (i *Interpreter) comprehend(name ast.Token, iterable ast.Expr, condition ast.Expr, produce func()) {
+	iterator, ok := iteratorFor(i.evaluate(iterable))
+	if !ok {
+		panic(utils.NewRuntimeError(name, "Can only iterate over lists, maps, sets, tuples, strings and ranges."))
+	}
+	defer releaseIterator(iterator)
+	previous := i.environment
+	defer func() { i.environment = previous }()
+	for {
+		value, ok := nextAt(name, iterator)
+		if !ok {
+			break
+		}
+		loop_env := NewEnvironmentWithEnclosing(&previous)
+		loop_env.define(name.Lexeme, value)
+		i.environment = loop_env
+		if condition == nil || utils.IsTruthy(i.evaluate(condition)) {
+			produce()
 		}
-		return function.call(*i, arguments)
 	}
-	log.Fatal("not a LoxCallable")
-	return nil
+}
+
+func (i *Interpreter) VisitExpr_ListComprehension(e ast.Expr_ListComprehension) any {
+	elements := []any{}
+	i.comprehend(e.Name, e.Iterable, e.Condition, func() {
+		elements = append(elements, i.evaluate(e.Element))
+	})
+	return NewLoxList(elements)
+}
+
+func (i *Interpreter) VisitExpr_MapComprehension(e ast.Expr_MapComprehension) any {
+	lmap := NewLoxMap()
+	i.comprehend(e.Name, e.Iterable, e.Condition, func() {
+		lmap.setKey(e.Brace, i.evaluate(e.Key), i.evaluate(e.Value))
+	})
+	return lmap
+}
+
+func (i *Interpreter) VisitExpr_Map(e ast.Expr_Map) any {
+	lmap := NewLoxMap()
+	for index, key := range e.Keys {
+		lmap.setKey(e.Brace, i.evaluate(key), i.evaluate(e.Values[index]))
+	}
+	return lmap
+}
+
+func (i *Interpreter) VisitExpr_SetLiteral(e ast.Expr_SetLiteral) any {
+	set := NewLoxSet()
+	for _, element := range i.evaluateElements(e.Elements) {
+		withNativeErrorAt(e.Brace, func() { set.add(element) })
+	}
+	return set
+}
+
+func (i *Interpreter) VisitExpr_Index(e ast.Expr_Index) any {
+	object := i.evaluate(e.Object)
+	index := i.evaluate(e.Index)
+	if indexes, ok := index.(*LoxRange); ok {
+		return slice(e.Bracket, object, indexes)
+	}
+
+	switch object := object.(type) {
+	case *LoxList:
+		return object.getIndex(e.Bracket, index)
+	case *LoxMap:
+		return object.getKey(e.Bracket, index)
+	case *LoxTuple:
+		return object.getIndex(e.Bracket, index)
+	case string:
+		return stringIndex(e.Bracket, object, index)
+	case *LoxRange:
+		return object.getIndex(e.Bracket, index)
+	case *LoxBytes:
+		return object.getIndex(e.Bracket, index)
+	case *LoxBuffer:
+		return object.getIndex(e.Bracket, index)
+	}
+	panic(utils.NewRuntimeError(e.Bracket, "Only lists, maps, tuples, strings and bytes can be indexed."))
+}
+
+func (i *Interpreter) VisitExpr_IndexSet(e ast.Expr_IndexSet) any {
+	object := i.evaluate(e.Object)
+	index := i.evaluate(e.Index)
+	value := i.evaluate(e.Value)
+
+	switch object := object.(type) {
+	case *LoxList:
+		object.setIndex(e.Bracket, index, value)
+	case *LoxMap:
+		object.setKey(e.Bracket, index, value)
+	case *LoxTuple:
+		panic(utils.NewRuntimeError(e.Bracket, "Tuples are immutable."))
+	case *LoxBuffer:
+		object.setIndex(e.Bracket, index, value)
+	case string:
+		panic(utils.NewRuntimeError(e.Bracket, "Strings are immutable."))
+	case *LoxBytes:
+		panic(utils.NewRuntimeError(e.Bracket, "Bytes are immutable; use a buffer."))
+	default:
+		panic(utils.NewRuntimeError(e.Bracket, "Only lists, maps and buffers support index assignment."))
+	}
+	return value
+}
+
+func (i *Interpreter) VisitExpr_Get(e ast.Expr_Get) any {
+	object := i.evaluate(e.Object)
+	if object, ok := object.(LoxObject); ok {
+		return object.get(e.Name)
+	}
+	if s, ok := object.(string); ok {
+		return stringGet(s, e.Name)
+	}
+	panic(utils.NewRuntimeError(e.Name, "Only objects have properties."))
+}
+
+func (i *Interpreter) VisitExpr_Set(e ast.Expr_Set) any {
+	object := i.evaluate(e.Object)
+	if instance, ok := object.(*LoxRecordInstance); ok {
+		panic(utils.NewRuntimeError(e.Name, "Can't assign to field '"+e.Name.Lexeme+"' of immutable record "+instance.record.name+"."))
+	}
+	panic(utils.NewRuntimeError(e.Name, "Only instances have fields."))
+}
+
+func (i *Interpreter) VisitExpr_Range(e ast.Expr_Range) any {
+	start, okStart := i.evaluate(e.Start).(float64)
+	end, okEnd := i.evaluate(e.End).(float64)
+	if !okStart || !okEnd {
+		panic(utils.NewRuntimeError(e.Operator, "Range bounds must be numbers."))
+	}
+	if math.IsNaN(start) || math.IsNaN(end) {
+		panic(utils.NewRuntimeError(e.Operator, "Range bounds can't be NaN."))
+	}
+	return &LoxRange{
+		start:     start,
+		end:       end,
+		step:      1,
+		inclusive: e.Operator.TokenType == ast.DOT_DOT,
+	}
+}
+
+func (i *Interpreter) VisitExpr_Tuple(e ast.Expr_Tuple) any {
+	elements := make([]any, len(e.Elements))
+	for index, element := range e.Elements {
+		elements[index] = i.evaluate(element)
+	}
+	return NewLoxTuple(elements)
 }
 
 func (i *Interpreter) VisitExpr_Grouping(e ast.Expr_Grouping) any {
@@ -150,6 +424,34 @@ func (i *Interpreter) checkNumberOperands(operator ast.Token, left any, right an
 
 }
 
+// stringify converts a Lox value into the text that print shows for it.
+func stringify(object any) string {
+	switch v := object.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return v
+	case float64:
+		// Whole numbers print without an exponent up to the point where
+		// float64 stops representing every integer exactly.
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		}
+	case interface{ toString() string }:
+		return v.toString()
+	}
+	return fmt.Sprintf("%v", object)
+}
+
+// repr is like stringify, but quotes strings so they stand out when nested
+// inside a list or map.
+func repr(object any) string {
+	if v, ok := object.(string); ok {
+		return strconv.Quote(v)
+	}
+	return stringify(object)
+}
+
 // func (i *Interpreter) castToString(object any) string {
 // 	if object == nil {
 // 		return "nil"
@@ -178,6 +480,7 @@ func (i *Interpreter) execute(stmt ast.Stmt) {
 	if stmt == nil {
 		panic("nil statement at execute()")
 	}
+	i.scheduler.tick(i)
 	stmt.Accept(i)
 }
 
@@ -208,7 +511,7 @@ func (i *Interpreter) VisitStmt_Expression(stmt ast.Stmt_Expression) {
 }
 
 func (i *Interpreter) VisitStmt_Function(stmt ast.Stmt_Function) {
-	function := LoxFunction{stmt, i.environment}
+	function := &LoxFunction{stmt, i.environment}
 	i.environment.define(stmt.Name.Lexeme, function)
 }
 
@@ -222,7 +525,15 @@ func (i *Interpreter) VisitStmt_If(stmt ast.Stmt_If) {
 
 func (i *Interpreter) VisitStmt_Print(stmt ast.Stmt_Print) {
 	value := i.evaluate(stmt.Expression)
-	fmt.Println(value)
+	fmt.Println(stringify(value))
+}
+
+func (i *Interpreter) VisitStmt_Record(stmt ast.Stmt_Record) {
+	fields := make([]string, len(stmt.Fields))
+	for index, field := range stmt.Fields {
+		fields[index] = field.Lexeme
+	}
+	i.environment.define(stmt.Name.Lexeme, &LoxRecord{stmt.Name.Lexeme, fields})
 }
 
 func (i *Interpreter) VisitStmt_Return(stmt ast.Stmt_Return) {
@@ -239,6 +550,24 @@ func (i *Interpreter) VisitStmt_While(stmt ast.Stmt_While) {
 	}
 }
 
+func (i *Interpreter) VisitStmt_ForIn(stmt ast.Stmt_ForIn) {
+	iterator, ok := iteratorFor(i.evaluate(stmt.Iterable))
+	if !ok {
+		panic(utils.NewRuntimeError(stmt.Name, "Can only iterate over lists, maps, sets, tuples, strings and ranges."))
+	}
+	defer releaseIterator(iterator)
+	for {
+		value, ok := nextAt(stmt.Name, iterator)
+		if !ok {
+			break
+		}
+		enclosing_env := i.environment
+		loop_env := NewEnvironmentWithEnclosing(&enclosing_env)
+		loop_env.define(stmt.Name.Lexeme, value)
+		i.executeBlock([]ast.Stmt{stmt.Body}, &loop_env)
+	}
+}
+
 func (i *Interpreter) VisitStmt_Var(stmt ast.Stmt_Var) {
 	var value any
 	if stmt.Initializer != nil {
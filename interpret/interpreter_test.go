@@ -0,0 +1,46 @@
+package interpret_test
+
+import "testing"
+
+func TestComprehensions(t *testing.T) {
+	expectOutput(t, `
+print [x * x for x in 1..4];
+print [x for x in 1..10 if x > 7];
+print {x: x * 2 for x in (1, 2)};
+print {k: k.upper() for k in ["a", "b"] if k != "a"};
+var x = "outer";
+print [x for x in [1]];
+print x;
+`, "[1, 4, 9, 16]\n[8, 9, 10]\n{1: 2, 2: 4}\n{\"b\": \"B\"}\n[1]\nouter\n", nil)
+}
+
+func TestSpread(t *testing.T) {
+	expectOutput(t, `
+var xs = [1, 2];
+print [0, ...xs, ...(3, 4), ...3..4];
+fun add(a, b, c) { return a + b + c; }
+print add(...xs, 3);
+print add(...[1, 2, 3]);
+`, "[0, 1, 2, 3, 4, 3, 4]\n6\n6\n", nil)
+}
+
+func TestSpreadErrors(t *testing.T) {
+	expectError(t, `
+fun f(a) { return a; }
+f(...[1, 2]);
+`, "Expected 1 arguments but got 2.", 3, nil)
+	expectError(t, `
+print [...1];
+`, "Can only spread iterable values.", 2, nil)
+}
+
+func TestPrintWholeNumbers(t *testing.T) {
+	expectOutput(t, `
+print 1000000 * 1000000;
+print -3000000;
+print time.now().add(time.day).unixMillis;
+print math.pow(2, 52);
+print math.pow(2, 53);
+print 2.5;
+`, "1000000000000\n-3000000\n1704153600000\n4503599627370496\n9.007199254740992e+15\n2.5\n", nil)
+}
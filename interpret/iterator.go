@@ -0,0 +1,73 @@
+package interpret
+
+import (
+	"github.com/kljablon/golox/ast"
+	"github.com/kljablon/golox/utils"
+)
+
+// LoxIterable is implemented by values that can be looped over with for-in.
+type LoxIterable interface {
+	iterator() LoxIterator
+}
+
+// LoxIterator yields the elements of a LoxIterable one at a time. The second
+// result is false once the iteration is exhausted.
+type LoxIterator interface {
+	next() (any, bool)
+}
+
+// releasingIterator is implemented by iterators that hold a resource, such
+// as an open file, until they are exhausted.
+type releasingIterator interface {
+	LoxIterator
+	release()
+}
+
+// releaseIterator releases the resources of iterator, if it holds any.
+// Loops that can stop before the end, through an error, a return or a
+// condition that is met early, defer it.
+func releaseIterator(iterator LoxIterator) {
+	if releasing, ok := iterator.(releasingIterator); ok {
+		releasing.release()
+	}
+}
+
+// iteratorFor returns an iterator over value, or false if value can't be
+// iterated.
+func iteratorFor(value any) (LoxIterator, bool) {
+	if iterable, ok := value.(LoxIterable); ok {
+		return iterable.iterator(), true
+	}
+	if s, ok := value.(string); ok {
+		return &stringIterator{runes: []rune(s)}, true
+	}
+	return nil, false
+}
+
+// nextAt advances iterator outside of any native call, such as in a for-in
+// loop, reporting a NativeError raised by the iterator at token.
+func nextAt(token ast.Token, iterator LoxIterator) (any, bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			if err, ok := r.(NativeError); ok {
+				panic(utils.NewRuntimeError(token, err.Message))
+			}
+			panic(r)
+		}
+	}()
+	return iterator.next()
+}
+
+type sliceIterator struct {
+	elements []any
+	index    int
+}
+
+func (s *sliceIterator) next() (any, bool) {
+	if s.index >= len(s.elements) {
+		return nil, false
+	}
+	value := s.elements[s.index]
+	s.index++
+	return value, true
+}
@@ -0,0 +1,216 @@
+package interpret
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// jsonParse converts JSON text into Lox values: objects become maps (keeping
+// the key order of the text), arrays become lists, and numbers, strings,
+// booleans and null become the matching Lox values.
+func jsonParse(text string) any {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	value := jsonValue(decoder)
+	if _, err := decoder.Token(); err != io.EOF {
+		panic(NativeError{"Invalid JSON: unexpected data after the top-level value."})
+	}
+	return value
+}
+
+func jsonToken(decoder *json.Decoder) json.Token {
+	token, err := decoder.Token()
+	if err == io.EOF {
+		panic(NativeError{"Invalid JSON: unexpected end of input."})
+	}
+	if err != nil {
+		panic(NativeError{"Invalid JSON: " + err.Error() + "."})
+	}
+	return token
+}
+
+func jsonValue(decoder *json.Decoder) any {
+	token := jsonToken(decoder)
+	switch token {
+	case json.Delim('['):
+		elements := []any{}
+		for decoder.More() {
+			elements = append(elements, jsonValue(decoder))
+		}
+		jsonToken(decoder)
+		return NewLoxList(elements)
+	case json.Delim('{'):
+		object := NewLoxMap()
+		for decoder.More() {
+			key := jsonToken(decoder).(string)
+			value := jsonValue(decoder)
+			if _, ok := object.values[key]; !ok {
+				object.keys = append(object.keys, key)
+			}
+			object.values[key] = value
+		}
+		jsonToken(decoder)
+		return object
+	}
+	return token
+}
+
+// jsonEncoder writes Lox values as JSON. seen holds the lists and maps that
+// are currently being written, to catch values that contain themselves.
+type jsonEncoder struct {
+	out    strings.Builder
+	indent string
+	seen   map[any]bool
+}
+
+// jsonStringify converts a Lox value to JSON text. With a non-empty indent
+// the output is spread over several lines.
+func jsonStringify(value any, indent string) string {
+	encoder := jsonEncoder{indent: indent, seen: make(map[any]bool)}
+	encoder.write(value, 0)
+	return encoder.out.String()
+}
+
+func (j *jsonEncoder) newline(depth int) {
+	if j.indent != "" {
+		j.out.WriteString("\n" + strings.Repeat(j.indent, depth))
+	}
+}
+
+func (j *jsonEncoder) writeString(s string) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+	j.out.WriteString(strings.TrimSuffix(buffer.String(), "\n"))
+}
+
+// enter marks a container as being written, failing if it already is.
+func (j *jsonEncoder) enter(container any) {
+	if j.seen[container] {
+		panic(NativeError{"Can't convert a value that contains itself to JSON."})
+	}
+	j.seen[container] = true
+}
+
+// writeArray writes elements as a JSON array.
+func (j *jsonEncoder) writeArray(elements []any, depth int) {
+	j.out.WriteString("[")
+	for index, element := range elements {
+		if index > 0 {
+			j.out.WriteString(",")
+		}
+		j.newline(depth + 1)
+		j.write(element, depth+1)
+	}
+	if len(elements) > 0 {
+		j.newline(depth)
+	}
+	j.out.WriteString("]")
+}
+
+// writeObject writes keys and values as a JSON object.
+func (j *jsonEncoder) writeObject(keys []any, values []any, depth int) {
+	separator := ":"
+	if j.indent != "" {
+		separator = ": "
+	}
+	j.out.WriteString("{")
+	for index, key := range keys {
+		if index > 0 {
+			j.out.WriteString(",")
+		}
+		j.newline(depth + 1)
+		if key == nil {
+			j.writeString("null")
+		} else {
+			j.writeString(stringify(key))
+		}
+		j.out.WriteString(separator)
+		j.write(values[index], depth+1)
+	}
+	if len(keys) > 0 {
+		j.newline(depth)
+	}
+	j.out.WriteString("}")
+}
+
+func (j *jsonEncoder) write(value any, depth int) {
+	switch v := value.(type) {
+	case nil:
+		j.out.WriteString("null")
+	case bool:
+		j.out.WriteString(strconv.FormatBool(v))
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			panic(NativeError{"Can't convert " + stringify(v) + " to JSON."})
+		}
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			j.out.WriteString(strconv.FormatFloat(v, 'f', -1, 64))
+		} else {
+			j.out.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+		}
+	case string:
+		j.writeString(v)
+	case *LoxList:
+		j.enter(v)
+		j.writeArray(v.elements, depth)
+		delete(j.seen, v)
+	case *LoxTuple:
+		j.writeArray(v.elements, depth)
+	case *LoxSet:
+		j.writeArray(v.elements, depth)
+	case *LoxMap:
+		j.enter(v)
+		values := make([]any, len(v.keys))
+		for index, key := range v.keys {
+			values[index] = v.valueOf(key)
+		}
+		j.writeObject(v.keys, values, depth)
+		delete(j.seen, v)
+	case *LoxRecordInstance:
+		keys := make([]any, len(v.record.fields))
+		for index, field := range v.record.fields {
+			keys[index] = field
+		}
+		j.writeObject(keys, v.values, depth)
+	default:
+		panic(NativeError{"Can't convert " + stringify(value) + " to JSON."})
+	}
+}
+
+// maxJsonIndent bounds the number of spaces stringify indents by.
+const maxJsonIndent = 10
+
+func newJsonModule() *LoxModule {
+	module := NewLoxModule("json")
+
+	module.DefineNative(native("parse", []ArgType{STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		return jsonParse(arguments[0].(string))
+	}))
+	module.DefineNative(NewNativeFunction("stringify", 1, 2, []ArgType{ANY_ARG, ANY_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		indent := ""
+		if len(arguments) == 2 {
+			switch v := arguments[1].(type) {
+			case float64:
+				if v < 0 || math.IsInf(v, 0) || v != math.Trunc(v) {
+					panic(NativeError{"JSON indent must be a non-negative integer."})
+				}
+				if v > maxJsonIndent {
+					panic(NativeError{"JSON indent can't be more than " + strconv.Itoa(maxJsonIndent) + "."})
+				}
+				indent = strings.Repeat(" ", int(v))
+			case string:
+				indent = v
+			case nil:
+			default:
+				panic(NativeError{"JSON indent must be a number or a string."})
+			}
+		}
+		return jsonStringify(arguments[0], indent)
+	}))
+	return module
+}
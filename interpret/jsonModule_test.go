@@ -0,0 +1,86 @@
+package interpret_test
+
+import (
+	"testing"
+
+	"github.com/kljablon/golox/interpret"
+)
+
+// withArgs passes args to the script, which is how tests get text containing
+// quotes into it.
+func withArgs(args ...string) func(i *interpret.Interpreter) {
+	return func(i *interpret.Interpreter) {
+		i.SetArgs(args)
+	}
+}
+
+func TestJsonParse(t *testing.T) {
+	expectOutput(t, `
+var v = json.parse(args[0]);
+print v;
+print v["b"][1];
+print json.parse("3");
+`, "{\"b\": [1, 2.5, true, nil], \"a\": \"xé\"}\n2.5\n3\n", withArgs(`{"b": [1, 2.5, true, null], "a": "xé"}`))
+}
+
+func TestJsonStringify(t *testing.T) {
+	expectOutput(t, `
+print json.stringify(json.parse(args[0]));
+print json.stringify(args[1]);
+print json.stringify((1, 2));
+print json.stringify({1: 2});
+`, `{"b":[1,2.5,true,null],"a":"xé"}
+"quote\"d"
+[1,2]
+{"1":2}
+`, withArgs(`{"b": [1, 2.5, true, null], "a": "xé"}`, `quote"d`))
+}
+
+func TestJsonIndent(t *testing.T) {
+	expectOutput(t, `
+print json.stringify({"k": [1, {"n": nil}], "e": []}, 2);
+print json.stringify([1, 2], "--");
+`, `{
+  "k": [
+    1,
+    {
+      "n": null
+    }
+  ],
+  "e": []
+}
+[
+--1,
+--2
+]
+`, nil)
+}
+
+func TestJsonErrors(t *testing.T) {
+	expectError(t, `
+json.parse("{bad");
+`, "Invalid JSON: invalid character 'b' looking for beginning of value.", 2, nil)
+	expectError(t, `
+json.stringify(clock);
+`, "Can't convert <native fn clock> to JSON.", 2, nil)
+	expectError(t, `
+json.stringify(math.nan);
+`, "Can't convert NaN to JSON.", 2, nil)
+	expectError(t, `
+var l = [];
+l.push(l);
+json.stringify(l);
+`, "Can't convert a value that contains itself to JSON.", 4, nil)
+}
+
+func TestJsonIndentLimits(t *testing.T) {
+	expectOutput(t, `
+print json.stringify([1], 10);
+`, "[\n          1\n]\n", nil)
+	expectError(t, `
+json.stringify(1, 1/0);
+`, "JSON indent must be a non-negative integer.", 2, nil)
+	expectError(t, `
+json.stringify([1], 100000000000);
+`, "JSON indent can't be more than 10.", 2, nil)
+}
@@ -0,0 +1,84 @@
+package interpret
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/kljablon/golox/ast"
+	"github.com/kljablon/golox/utils"
+)
+
+// LoxBuffer is a growable, mutable sequence of bytes for building up binary
+// data. Its elements can be assigned by index, and bytes() takes a snapshot
+// of it.
+type LoxBuffer struct {
+	data []byte
+}
+
+// newBuffer implements the buffer() constructor: buffer() is empty,
+// buffer(n) holds n zero bytes, and otherwise it takes the same arguments as
+// bytes().
+func newBuffer(interpreter *Interpreter, arguments []any) any {
+	if len(arguments) == 0 {
+		return &LoxBuffer{[]byte{}}
+	}
+	if size, ok := arguments[0].(float64); ok && len(arguments) == 1 {
+		if size != math.Trunc(size) || size < 0 {
+			panic(NativeError{"Buffer size must be a non-negative integer."})
+		}
+		if size > maxLength {
+			panic(NativeError{"Buffer size can't be more than " + strconv.Itoa(maxLength) + " bytes."})
+		}
+		return &LoxBuffer{make([]byte, int(size))}
+	}
+	return &LoxBuffer{dataFrom(arguments[0], optionalEncoding(arguments, 1))}
+}
+
+func (b *LoxBuffer) get(name ast.Token) any {
+	if value, ok := dataGet(func() []byte { return b.data }, name); ok {
+		return value
+	}
+	switch name.Lexeme {
+	case "write":
+		return NewNativeFunction("write", 1, 2, []ArgType{DATA_ARG, STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			if text, ok := arguments[0].(string); ok {
+				b.data = append(b.data, encodeText(text, optionalEncoding(arguments, 1))...)
+			} else {
+				b.data = append(b.data, toData(arguments[0])...)
+			}
+			return nil
+		})
+	case "push":
+		return native("push", []ArgType{ANY_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			b.data = append(b.data, toByte(arguments[0]))
+			return nil
+		})
+	case "clear":
+		return native("clear", nil, func(interpreter *Interpreter, arguments []any) any {
+			b.data = b.data[:0]
+			return nil
+		})
+	}
+	panic(utils.NewRuntimeError(name, "Undefined property '"+name.Lexeme+"'."))
+}
+
+func (b *LoxBuffer) getIndex(bracket ast.Token, index any) any {
+	return float64(b.data[toIndex(bracket, index, len(b.data))])
+}
+
+func (b *LoxBuffer) setIndex(bracket ast.Token, index any, value any) {
+	position := toIndex(bracket, index, len(b.data))
+	number, ok := value.(float64)
+	if !ok || number != float64(int(number)) || number < 0 || number > 255 {
+		panic(utils.NewRuntimeError(bracket, "Byte values must be integers from 0 to 255."))
+	}
+	b.data[position] = byte(number)
+}
+
+func (b *LoxBuffer) iterator() LoxIterator {
+	return &byteIterator{data: b.data}
+}
+
+func (b *LoxBuffer) toString() string {
+	return "buffer(" + quoteBytes(b.data) + ")"
+}
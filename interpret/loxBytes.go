@@ -0,0 +1,257 @@
+package interpret
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/kljablon/golox/ast"
+	"github.com/kljablon/golox/utils"
+)
+
+// LoxBytes is an immutable sequence of bytes, for binary data such as
+// digests and decoded base64 that isn't necessarily valid text. Indexing it
+// gives numbers from 0 to 255.
+type LoxBytes struct {
+	data []byte
+}
+
+func NewLoxBytes(data []byte) *LoxBytes {
+	return &LoxBytes{data}
+}
+
+// toData returns the bytes of a string, bytes or buffer value; its argument
+// has already been checked with DATA_ARG.
+func toData(value any) []byte {
+	switch v := value.(type) {
+	case *LoxBytes:
+		return v.data
+	case *LoxBuffer:
+		return v.data
+	}
+	return []byte(value.(string))
+}
+
+// toByte checks that value is a number that fits in a byte.
+func toByte(value any) byte {
+	number, ok := value.(float64)
+	if !ok || number != float64(int(number)) || number < 0 || number > 255 {
+		panic(NativeError{"Byte values must be integers from 0 to 255."})
+	}
+	return byte(number)
+}
+
+// dataFrom builds bytes from the argument of bytes() or buffer(): text in the
+// given encoding, other binary data, or a list of byte values.
+func dataFrom(value any, encoding string) []byte {
+	switch v := value.(type) {
+	case string:
+		return encodeText(v, encoding)
+	case *LoxBytes:
+		return append([]byte{}, v.data...)
+	case *LoxBuffer:
+		return append([]byte{}, v.data...)
+	}
+	iterator, ok := iteratorFor(value)
+	if !ok {
+		panic(NativeError{"Can't make bytes from " + stringify(value) + "."})
+	}
+	defer releaseIterator(iterator)
+	data := []byte{}
+	for {
+		element, ok := iterator.next()
+		if !ok {
+			return data
+		}
+		data = append(data, toByte(element))
+	}
+}
+
+// normalizeEncoding accepts the names of the supported encodings in either
+// case, with or without '-' and '_': utf-8, ascii, latin-1, utf-16le and
+// utf-16be.
+func normalizeEncoding(encoding string) string {
+	name := strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(encoding))
+	switch name {
+	case "utf8", "ascii", "latin1", "utf16le", "utf16be":
+		return name
+	}
+	panic(NativeError{"Unknown encoding '" + encoding + "'."})
+}
+
+func encodeText(text string, encoding string) []byte {
+	switch name := normalizeEncoding(encoding); name {
+	case "ascii", "latin1":
+		limit := rune(0x7f)
+		if name == "latin1" {
+			limit = 0xff
+		}
+		data := make([]byte, 0, len(text))
+		for _, r := range text {
+			if r > limit {
+				panic(NativeError{fmt.Sprintf("Can't encode %q as %s.", r, encoding)})
+			}
+			data = append(data, byte(r))
+		}
+		return data
+	case "utf16le", "utf16be":
+		units := utf16.Encode([]rune(text))
+		data := make([]byte, 0, 2*len(units))
+		for _, unit := range units {
+			if name == "utf16le" {
+				data = append(data, byte(unit), byte(unit>>8))
+			} else {
+				data = append(data, byte(unit>>8), byte(unit))
+			}
+		}
+		return data
+	}
+	return []byte(text)
+}
+
+func decodeText(data []byte, encoding string) string {
+	switch name := normalizeEncoding(encoding); name {
+	case "ascii", "latin1":
+		runes := make([]rune, len(data))
+		for index, b := range data {
+			if name == "ascii" && b > 0x7f {
+				panic(NativeError{fmt.Sprintf("Byte %d is not valid ascii.", b)})
+			}
+			runes[index] = rune(b)
+		}
+		return string(runes)
+	case "utf16le", "utf16be":
+		if len(data)%2 != 0 {
+			panic(NativeError{"Bytes are not valid " + encoding + " text."})
+		}
+		units := make([]uint16, len(data)/2)
+		for index := range units {
+			if name == "utf16le" {
+				units[index] = uint16(data[2*index]) | uint16(data[2*index+1])<<8
+			} else {
+				units[index] = uint16(data[2*index])<<8 | uint16(data[2*index+1])
+			}
+		}
+		return string(utf16.Decode(units))
+	}
+	if !utf8.Valid(data) {
+		panic(NativeError{"Bytes are not valid UTF-8 text."})
+	}
+	return string(data)
+}
+
+// optionalEncoding reads an encoding argument, defaulting to UTF-8.
+func optionalEncoding(arguments []any, position int) string {
+	if len(arguments) > position {
+		return arguments[position].(string)
+	}
+	return "utf-8"
+}
+
+// newBytes implements the bytes() constructor: bytes(text, encoding) encodes
+// a string, and bytes(data) copies bytes, a buffer or a list of byte values.
+func newBytes(interpreter *Interpreter, arguments []any) any {
+	if len(arguments) == 0 {
+		return NewLoxBytes([]byte{})
+	}
+	return NewLoxBytes(dataFrom(arguments[0], optionalEncoding(arguments, 1)))
+}
+
+// dataGet provides the read-only members shared by bytes and buffers. data
+// is called each time a member is used, so a method read from a buffer sees
+// what the buffer holds when it is called rather than when it was read.
+func dataGet(data func() []byte, name ast.Token) (any, bool) {
+	switch name.Lexeme {
+	case "length":
+		return float64(len(data())), true
+	case "text":
+		return NewNativeFunction("text", 0, 1, []ArgType{STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			return decodeText(data(), optionalEncoding(arguments, 0))
+		}), true
+	case "hex":
+		return native("hex", nil, func(interpreter *Interpreter, arguments []any) any {
+			return fmt.Sprintf("%x", data())
+		}), true
+	case "toList":
+		return native("toList", nil, func(interpreter *Interpreter, arguments []any) any {
+			current := data()
+			elements := make([]any, len(current))
+			for index, b := range current {
+				elements[index] = float64(b)
+			}
+			return NewLoxList(elements)
+		}), true
+	case "indexOf":
+		return native("indexOf", []ArgType{DATA_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			return float64(bytes.Index(data(), toData(arguments[0])))
+		}), true
+	}
+	return nil, false
+}
+
+func (b *LoxBytes) get(name ast.Token) any {
+	if value, ok := dataGet(func() []byte { return b.data }, name); ok {
+		return value
+	}
+	if name.Lexeme == "concat" {
+		return native("concat", []ArgType{DATA_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			other := toData(arguments[0])
+			data := make([]byte, 0, len(b.data)+len(other))
+			return NewLoxBytes(append(append(data, b.data...), other...))
+		})
+	}
+	panic(utils.NewRuntimeError(name, "Undefined property '"+name.Lexeme+"'."))
+}
+
+func (b *LoxBytes) getIndex(bracket ast.Token, index any) any {
+	return float64(b.data[toIndex(bracket, index, len(b.data))])
+}
+
+func (b *LoxBytes) iterator() LoxIterator {
+	return &byteIterator{data: b.data}
+}
+
+func (b *LoxBytes) Equals(other any) bool {
+	o, ok := other.(*LoxBytes)
+	return ok && bytes.Equal(b.data, o.data)
+}
+
+func (b *LoxBytes) toString() string {
+	return quoteBytes(b.data)
+}
+
+// quoteBytes shows printable ASCII as is and every other byte as an escape.
+func quoteBytes(data []byte) string {
+	var out strings.Builder
+	out.WriteString(`b"`)
+	for _, c := range data {
+		switch {
+		case c == '"' || c == '\\':
+			out.WriteByte('\\')
+			out.WriteByte(c)
+		case c >= ' ' && c <= '~':
+			out.WriteByte(c)
+		default:
+			fmt.Fprintf(&out, `\x%02x`, c)
+		}
+	}
+	out.WriteString(`"`)
+	return out.String()
+}
+
+// byteIterator yields the bytes of bytes or a buffer as numbers.
+type byteIterator struct {
+	data  []byte
+	index int
+}
+
+func (b *byteIterator) next() (any, bool) {
+	if b.index >= len(b.data) {
+		return nil, false
+	}
+	value := float64(b.data[b.index])
+	b.index++
+	return value, true
+}
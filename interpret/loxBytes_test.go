@@ -0,0 +1,99 @@
+package interpret_test
+
+import "testing"
+
+func TestBytes(t *testing.T) {
+	expectOutput(t, `
+var b = bytes("héllo");
+print b;
+print b[1];
+print b.length;
+print b[0..<2];
+print b.text();
+print b.hex();
+print b.toList();
+print b.indexOf(bytes("llo"));
+for (x in bytes("ab")) print x;
+`, "b\"h\\xc3\\xa9llo\"\n195\n6\nb\"h\\xc3\"\nhéllo\n68c3a96c6c6f\n[104, 195, 169, 108, 108, 111]\n3\n97\n98\n", nil)
+}
+
+func TestBytesEncodings(t *testing.T) {
+	expectOutput(t, `
+print bytes("hé", "latin-1");
+print bytes("hi", "utf-16le");
+print bytes("hé", "utf-16be").text("utf-16be");
+print bytes([104, 105]).text();
+print bytes(bytes("x")) == bytes("x");
+`, "b\"h\\xe9\"\nb\"h\\x00i\\x00\"\nhé\nhi\ntrue\n", nil)
+}
+
+func TestBuffer(t *testing.T) {
+	expectOutput(t, `
+var buf = buffer();
+buf.write("ab");
+buf.push(99);
+buf[0] = 65;
+print buf;
+print buf.text();
+print buf.length;
+print buffer(3);
+print sha256(buf) == sha256("Abc");
+`, "buffer(b\"Abc\")\nAbc\n3\nbuffer(b\"\\x00\\x00\\x00\")\ntrue\n", nil)
+}
+
+func TestBytesFiles(t *testing.T) {
+	_, setup := sandbox(t)
+	expectOutput(t, `
+writeFile("data.bin", bytes([0, 255]));
+var buf = buffer();
+buf.write("!");
+appendFile("data.bin", buf);
+print readBytes("data.bin");
+`, "b\"\\x00\\xff!\"\n", setup)
+}
+
+func TestBytesErrors(t *testing.T) {
+	expectError(t, `
+bytes("é", "ascii");
+`, "Can't encode 'é' as ascii.", 2, nil)
+	expectError(t, `
+bytes([256]);
+`, "Byte values must be integers from 0 to 255.", 2, nil)
+	expectError(t, `
+bytes("x", "utf-32");
+`, "Unknown encoding 'utf-32'.", 2, nil)
+	expectError(t, `
+var b = bytes("x");
+b[0] = 1;
+`, "Bytes are immutable; use a buffer.", 3, nil)
+	expectError(t, `
+buffer().push(300);
+`, "Byte values must be integers from 0 to 255.", 2, nil)
+}
+
+func TestBufferSizeLimit(t *testing.T) {
+	expectError(t, `
+buffer(10000000000);
+`, "Buffer size can't be more than 1073741824 bytes.", 2, nil)
+	expectError(t, `
+buffer(1.5);
+`, "Buffer size must be a non-negative integer.", 2, nil)
+}
+
+func TestBufferMethodsReadCurrentData(t *testing.T) {
+	expectOutput(t, `
+var buf = buffer();
+buf.write("ab");
+var text = buf.text;
+var hex = buf.hex;
+var toList = buf.toList;
+var indexOf = buf.indexOf;
+buf.write("c");
+print text();
+print hex();
+print toList();
+print indexOf(bytes("c"));
+buf.clear();
+print text() == "";
+`, "abc\n616263\n[97, 98, 99]\n2\ntrue\n", nil)
+}
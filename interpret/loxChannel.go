@@ -0,0 +1,189 @@
+package interpret
+
+import (
+	"strconv"
+
+	"github.com/kljablon/golox/ast"
+	"github.com/kljablon/golox/utils"
+)
+
+// LoxChannel passes values between tasks. An unbuffered channel makes the
+// sender wait for a receiver; a buffered one holds up to its capacity of
+// values first. Iterating over a channel receives until it is closed.
+//
+// Channels are only used while holding the scheduler's lock, and keep their
+// own queues of parked senders and receivers so that the scheduler can tell
+// which tasks are waiting on others.
+type LoxChannel struct {
+	scheduler *scheduler
+	capacity  int
+	buffer    []any
+	closed    bool
+	senders   []channelWaiter
+	receivers []channelWaiter
+}
+
+// channelWaiter is a task parked on a channel: index is the select case it
+// came from and value the value it is sending.
+type channelWaiter struct {
+	waiter *waiter
+	index  int
+	value  any
+}
+
+// newChannel implements chan(): chan() is unbuffered and chan(n) holds up
+// to n values.
+func newChannel(interpreter *Interpreter, arguments []any) any {
+	capacity := 0
+	if len(arguments) == 1 {
+		capacity = int(arguments[0].(float64))
+		if capacity < 0 {
+			panic(NativeError{"Channel capacity can't be negative."})
+		}
+	}
+	return &LoxChannel{scheduler: interpreter.scheduler, capacity: capacity}
+}
+
+// popWaiter takes the first waiter from queue that hasn't already been woken
+// by another case of its select.
+func popWaiter(queue *[]channelWaiter) (channelWaiter, bool) {
+	for len(*queue) > 0 {
+		first := (*queue)[0]
+		*queue = (*queue)[1:]
+		if !first.waiter.fired {
+			return first, true
+		}
+	}
+	return channelWaiter{}, false
+}
+
+// trySend sends value without waiting, reporting whether it could.
+func (c *LoxChannel) trySend(value any) bool {
+	if c.closed {
+		panic(NativeError{"Can't send on a closed channel."})
+	}
+	if receiver, ok := popWaiter(&c.receivers); ok {
+		c.scheduler.wake(receiver.waiter, receiver.index, value, true)
+		return true
+	}
+	if len(c.buffer) < c.capacity {
+		c.buffer = append(c.buffer, value)
+		return true
+	}
+	return false
+}
+
+// tryReceive receives without waiting. ready is false if the receiver would
+// have to wait, and ok is false if the channel is closed and empty.
+func (c *LoxChannel) tryReceive() (value any, ok bool, ready bool) {
+	if len(c.buffer) > 0 {
+		value, c.buffer = c.buffer[0], c.buffer[1:]
+		if sender, found := popWaiter(&c.senders); found {
+			c.buffer = append(c.buffer, sender.value)
+			c.scheduler.wake(sender.waiter, sender.index, nil, true)
+		}
+		return value, true, true
+	}
+	if sender, found := popWaiter(&c.senders); found {
+		c.scheduler.wake(sender.waiter, sender.index, nil, true)
+		return sender.value, true, true
+	}
+	if c.closed {
+		return nil, false, true
+	}
+	return nil, false, false
+}
+
+func (c *LoxChannel) send(value any) {
+	if c.trySend(value) {
+		return
+	}
+	w := newWaiter()
+	c.senders = append(c.senders, channelWaiter{w, 0, value})
+	c.scheduler.park(w, nil)
+	if !w.ok {
+		panic(NativeError{"Can't send on a closed channel."})
+	}
+}
+
+// receive waits for the next value, reporting false once the channel is
+// closed and empty.
+func (c *LoxChannel) receive() (any, bool) {
+	if value, ok, ready := c.tryReceive(); ready {
+		return value, ok
+	}
+	w := newWaiter()
+	c.receivers = append(c.receivers, channelWaiter{w, 0, nil})
+	c.scheduler.park(w, nil)
+	return w.value, w.ok
+}
+
+// close wakes every waiting receiver with nil, and fails every waiting
+// sender.
+func (c *LoxChannel) close() {
+	if c.closed {
+		panic(NativeError{"Channel is already closed."})
+	}
+	c.closed = true
+	for _, queue := range []*[]channelWaiter{&c.receivers, &c.senders} {
+		for {
+			waiting, ok := popWaiter(queue)
+			if !ok {
+				break
+			}
+			c.scheduler.wake(waiting.waiter, waiting.index, nil, false)
+		}
+	}
+}
+
+// removeWaiter drops w from the channel's queues once its select is over.
+func (c *LoxChannel) removeWaiter(w *waiter) {
+	for _, queue := range []*[]channelWaiter{&c.receivers, &c.senders} {
+		kept := (*queue)[:0]
+		for _, waiting := range *queue {
+			if waiting.waiter != w {
+				kept = append(kept, waiting)
+			}
+		}
+		*queue = kept
+	}
+}
+
+func (c *LoxChannel) get(name ast.Token) any {
+	switch name.Lexeme {
+	case "send":
+		return native("send", []ArgType{ANY_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			c.send(arguments[0])
+			return nil
+		})
+	case "receive":
+		return native("receive", nil, func(interpreter *Interpreter, arguments []any) any {
+			value, _ := c.receive()
+			return value
+		})
+	case "close":
+		return native("close", nil, func(interpreter *Interpreter, arguments []any) any {
+			c.close()
+			return nil
+		})
+	case "closed":
+		return c.closed
+	case "length":
+		return float64(len(c.buffer))
+	case "capacity":
+		return float64(c.capacity)
+	}
+	panic(utils.NewRuntimeError(name, "Undefined property '"+name.Lexeme+"'."))
+}
+
+func (c *LoxChannel) iterator() LoxIterator {
+	return c
+}
+
+func (c *LoxChannel) next() (any, bool) {
+	return c.receive()
+}
+
+func (c *LoxChannel) toString() string {
+	return "<chan " + strconv.Itoa(c.capacity) + ">"
+}
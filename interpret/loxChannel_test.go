@@ -0,0 +1,53 @@
+package interpret_test
+
+import "testing"
+
+func TestSpawnAndChannels(t *testing.T) {
+	expectOutput(t, `
+var c = chan();
+fun producer(n) {
+  for (i in 0..<n) c.send(i);
+  c.close();
+}
+var task = spawn producer(3);
+for (v in c) print v;
+print c.closed;
+print c.receive();
+task.wait();
+print task.finished;
+print task;
+fun square(x) { return x * x; }
+print (spawn square(4)).wait();
+`, "0\n1\n2\ntrue\nnil\ntrue\n<task finished>\n16\n", nil)
+}
+
+func TestBufferedChannel(t *testing.T) {
+	expectOutput(t, `
+var b = chan(2);
+b.send("a");
+print b;
+print b.length;
+print b.capacity;
+print b.receive();
+`, "<chan 2>\n1\n2\na\n", nil)
+}
+
+func TestChannelErrors(t *testing.T) {
+	expectError(t, `
+var c = chan();
+c.close();
+c.send(1);
+`, "Can't send on a closed channel.", 4, nil)
+	expectError(t, `
+chan(-1);
+`, "Channel capacity can't be negative.", 2, nil)
+}
+
+func TestTaskErrorStopsScript(t *testing.T) {
+	expectError(t, `
+fun fail() { math.sqrt("a"); }
+spawn fail();
+var c = chan();
+c.receive();
+`, "Argument 1 to sqrt() must be a number.", 2, nil)
+}
@@ -6,9 +6,12 @@ import (
 	"github.com/kljablon/golox/ast"
 )
 
+// LoxCallable is implemented by every value that can be called. arity
+// returns the minimum and maximum number of arguments; a maximum of -1 means
+// there is no upper limit.
 type LoxCallable interface {
-	arity() int
-	call(interpreter Interpreter, arguments []any) any
+	arity() (int, int)
+	call(interpreter *Interpreter, arguments []any) any
 }
 
 type LoxFunction struct {
@@ -16,20 +19,30 @@ type LoxFunction struct {
 	closure     Environment
 }
 
-func (l *LoxFunction) call(interpreter Interpreter, arguments []any) (result any) {
+// call runs the function, or for an async function starts it and returns
+// the promise of its result.
+func (l *LoxFunction) call(interpreter *Interpreter, arguments []any) any {
+	if l.declaration.Async {
+		return interpreter.startAsync(func(interpreter *Interpreter) any {
+			return l.invoke(interpreter, arguments)
+		})
+	}
+	return l.invoke(interpreter, arguments)
+}
+
+func (l *LoxFunction) invoke(interpreter *Interpreter, arguments []any) (result any) {
 	environment := NewEnvironmentWithEnclosing(&l.closure)
-	for i, _ := range l.declaration.Params {
-		if arg, ok := arguments[i].(ast.Expr); ok {
-			arguments[i] = interpreter.evaluate(arg)
-		}
-		environment.define(l.declaration.Params[i].Lexeme, arguments[i])
+	for i, param := range l.declaration.Params {
+		environment.define(param.Lexeme, arguments[i])
 	}
 
 	defer func() {
 		if r := recover(); r != nil {
 			if r, ok := r.(Return); ok {
 				result = r.value
+				return
 			}
+			panic(r)
 		}
 	}()
 
@@ -37,8 +50,8 @@ func (l *LoxFunction) call(interpreter Interpreter, arguments []any) (result any
 	return result
 }
 
-func (l *LoxFunction) arity() int {
-	return len(l.declaration.Params)
+func (l *LoxFunction) arity() (int, int) {
+	return len(l.declaration.Params), len(l.declaration.Params)
 }
 
 func (l *LoxFunction) toString() string {
@@ -0,0 +1,88 @@
+package interpret
+
+import (
+	"math"
+	"strings"
+
+	"github.com/kljablon/golox/ast"
+	"github.com/kljablon/golox/utils"
+)
+
+type LoxList struct {
+	elements []any
+}
+
+func NewLoxList(elements []any) *LoxList {
+	return &LoxList{elements}
+}
+
+func (l *LoxList) get(name ast.Token) any {
+	switch name.Lexeme {
+	case "length":
+		return float64(len(l.elements))
+	case "push":
+		return native("push", []ArgType{ANY_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			l.elements = append(l.elements, arguments[0])
+			return nil
+		})
+	case "pop":
+		return native("pop", nil, func(interpreter *Interpreter, arguments []any) any {
+			if len(l.elements) == 0 {
+				panic(utils.NewRuntimeError(name, "Can't pop from an empty list."))
+			}
+			last := l.elements[len(l.elements)-1]
+			l.elements = l.elements[:len(l.elements)-1]
+			return last
+		})
+	}
+	panic(utils.NewRuntimeError(name, "Undefined property '"+name.Lexeme+"'."))
+}
+
+func (l *LoxList) getIndex(bracket ast.Token, index any) any {
+	return l.elements[toIndex(bracket, index, len(l.elements))]
+}
+
+func (l *LoxList) setIndex(bracket ast.Token, index any, value any) {
+	l.elements[toIndex(bracket, index, len(l.elements))] = value
+}
+
+func (l *LoxList) iterator() LoxIterator {
+	return &listIterator{l, 0}
+}
+
+func (l *LoxList) toString() string {
+	parts := make([]string, len(l.elements))
+	for i, element := range l.elements {
+		parts[i] = repr(element)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// listIterator reads the list as it goes, so elements pushed while looping
+// are visited too.
+type listIterator struct {
+	list  *LoxList
+	index int
+}
+
+func (l *listIterator) next() (any, bool) {
+	if l.index >= len(l.list.elements) {
+		return nil, false
+	}
+	value := l.list.elements[l.index]
+	l.index++
+	return value, true
+}
+
+// toIndex checks that index is a whole number within [0, length) and
+// converts it to an int.
+func toIndex(bracket ast.Token, index any, length int) int {
+	number, ok := index.(float64)
+	if !ok || number != math.Trunc(number) {
+		panic(utils.NewRuntimeError(bracket, "Index must be an integer."))
+	}
+	if number < 0 || number >= float64(length) {
+		panic(utils.NewRuntimeError(bracket, "Index out of range."))
+	}
+	return int(number)
+}
@@ -0,0 +1,38 @@
+package interpret_test
+
+import "testing"
+
+func TestList(t *testing.T) {
+	expectOutput(t, `
+var l = [1, "two", nil, true];
+l.push(5);
+print l;
+print l.pop();
+print l.length;
+l[0] = 7;
+print l[0];
+for (x in [1, [2, 3]]) print x;
+print [];
+`, "[1, \"two\", nil, true, 5]\n5\n4\n7\n1\n[2, 3]\n[]\n", nil)
+}
+
+func TestListIndexErrors(t *testing.T) {
+	expectError(t, `
+var l = [1];
+print l[1];
+`, "Index out of range.", 3, nil)
+	expectError(t, `
+print [1][0.5];
+`, "Index must be an integer.", 2, nil)
+	expectError(t, `
+var l = [];
+l.pop();
+`, "Can't pop from an empty list.", 3, nil)
+}
+
+func TestForInNeedsIterable(t *testing.T) {
+	expectError(t, `
+var x = 1;
+for (a in x) print a;
+`, "Can only iterate over lists, maps, sets, tuples, strings and ranges.", 3, nil)
+}
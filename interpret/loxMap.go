@@ -0,0 +1,189 @@
+package interpret
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kljablon/golox/ast"
+	"github.com/kljablon/golox/utils"
+)
+
+// LoxMap is a dictionary that remembers the order its keys were inserted in.
+// Keys are compared with utils.IsEqual. values is indexed by hashKey, so
+// equal tuples, records and bytes find the same entry.
+type LoxMap struct {
+	keys   []any
+	values map[any]any
+}
+
+func NewLoxMap() *LoxMap {
+	return &LoxMap{
+		keys:   []any{},
+		values: make(map[any]any),
+	}
+}
+
+// compositeKey is the hash key of a tuple, record or bytes value. Being its
+// own type, it never collides with a string key.
+type compositeKey string
+
+// hashKey returns the Go map key for a map key or set element. nil,
+// booleans, numbers and strings are their own keys. Tuples, records and
+// bytes are encoded from their contents, so equal values share a key. Lists,
+// maps, sets and other mutable values aren't hashable, and ok is false.
+func hashKey(value any) (key any, ok bool) {
+	switch value.(type) {
+	case nil, bool, string:
+		return value, true
+	case float64:
+		// -0 and 0 are equal, so they must be the same key.
+		if value == 0.0 {
+			return 0.0, true
+		}
+		return value, true
+	}
+	var out strings.Builder
+	if !writeKey(&out, value) {
+		return nil, false
+	}
+	return compositeKey(out.String()), true
+}
+
+// writeKey encodes value into out, reporting false if it isn't hashable.
+func writeKey(out *strings.Builder, value any) bool {
+	switch v := value.(type) {
+	case nil:
+		out.WriteString("nil")
+	case bool:
+		out.WriteString(strconv.FormatBool(v))
+	case float64:
+		if v == 0 {
+			v = 0
+		}
+		out.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+	case string:
+		out.WriteString(strconv.Quote(v))
+	case *LoxBytes:
+		out.WriteString("b" + strconv.Quote(string(v.data)))
+	case *LoxTuple:
+		return writeKeys(out, "(", v.elements)
+	case *LoxRecordInstance:
+		// Records of different declarations are never equal, even with the
+		// same name, so the key includes the declaration's identity.
+		fmt.Fprintf(out, "%s@%p", v.record.name, v.record)
+		return writeKeys(out, "(", v.values)
+	default:
+		return false
+	}
+	return true
+}
+
+func writeKeys(out *strings.Builder, open string, elements []any) bool {
+	out.WriteString(open)
+	for _, element := range elements {
+		if !writeKey(out, element) {
+			return false
+		}
+		out.WriteString(",")
+	}
+	out.WriteString(")")
+	return true
+}
+
+// isHashable reports whether value can be a map key or set element.
+func isHashable(value any) bool {
+	_, ok := hashKey(value)
+	return ok
+}
+
+// checkHashable returns the hash key of key, reporting a runtime error if it
+// can't be used as a map key.
+func checkHashable(token ast.Token, key any) any {
+	hashed, ok := hashKey(key)
+	if !ok {
+		panic(utils.NewRuntimeError(token, "Unhashable map key '"+stringify(key)+"'. Keys must be nil, booleans, numbers, strings, bytes, or tuples and records of those."))
+	}
+	return hashed
+}
+
+// valueOf returns the value of a key taken from m.keys.
+func (m *LoxMap) valueOf(key any) any {
+	hashed, _ := hashKey(key)
+	return m.values[hashed]
+}
+
+func (m *LoxMap) getKey(token ast.Token, key any) any {
+	return m.values[checkHashable(token, key)]
+}
+
+func (m *LoxMap) setKey(token ast.Token, key any, value any) {
+	hashed := checkHashable(token, key)
+	if _, ok := m.values[hashed]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[hashed] = value
+}
+
+func (m *LoxMap) has(token ast.Token, key any) bool {
+	_, ok := m.values[checkHashable(token, key)]
+	return ok
+}
+
+func (m *LoxMap) remove(token ast.Token, key any) any {
+	hashed := checkHashable(token, key)
+	value, ok := m.values[hashed]
+	if !ok {
+		return nil
+	}
+	delete(m.values, hashed)
+	for i, k := range m.keys {
+		if utils.IsEqual(k, key) {
+			m.keys = append(m.keys[:i], m.keys[i+1:]...)
+			break
+		}
+	}
+	return value
+}
+
+func (m *LoxMap) get(name ast.Token) any {
+	switch name.Lexeme {
+	case "length":
+		return float64(len(m.keys))
+	case "has":
+		return native("has", []ArgType{ANY_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			return m.has(name, arguments[0])
+		})
+	case "remove":
+		return native("remove", []ArgType{ANY_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			return m.remove(name, arguments[0])
+		})
+	case "keys":
+		return native("keys", nil, func(interpreter *Interpreter, arguments []any) any {
+			return NewLoxList(append([]any{}, m.keys...))
+		})
+	case "values":
+		return native("values", nil, func(interpreter *Interpreter, arguments []any) any {
+			values := make([]any, len(m.keys))
+			for i, key := range m.keys {
+				values[i] = m.valueOf(key)
+			}
+			return NewLoxList(values)
+		})
+	}
+	panic(utils.NewRuntimeError(name, "Undefined property '"+name.Lexeme+"'."))
+}
+
+// iterator yields the keys in insertion order. It works on a snapshot, so the
+// map may be modified inside the loop body.
+func (m *LoxMap) iterator() LoxIterator {
+	return &sliceIterator{elements: append([]any{}, m.keys...)}
+}
+
+func (m *LoxMap) toString() string {
+	parts := make([]string, len(m.keys))
+	for i, key := range m.keys {
+		parts[i] = repr(key) + ": " + repr(m.valueOf(key))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
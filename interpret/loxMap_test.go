@@ -0,0 +1,37 @@
+package interpret_test
+
+import "testing"
+
+func TestMapLiteral(t *testing.T) {
+	expectOutput(t, `
+var m = {"a": 1, "b": 2};
+m["c"] = 3;
+m["a"] = 10;
+print m;
+print m["a"];
+print m["missing"];
+print m.length;
+print {1: "one", nil: "nil", true: "yes"};
+print {};
+`, "{\"a\": 10, \"b\": 2, \"c\": 3}\n10\nnil\n3\n{1: \"one\", nil: \"nil\", true: \"yes\"}\n{}\n", nil)
+}
+
+func TestMapMethods(t *testing.T) {
+	expectOutput(t, `
+var m = {"a": 1, "b": 2, "c": 3};
+print m.has("b");
+print m.remove("b");
+print m.has("b");
+print m.remove("b");
+print m.keys();
+print m.values();
+for (key in m) print key;
+`, "true\n2\nfalse\nnil\n[\"a\", \"c\"]\n[1, 3]\na\nc\n", nil)
+}
+
+func TestMapUnhashableKey(t *testing.T) {
+	expectError(t, `
+var m = {};
+m[[1]] = 2;
+`, "Unhashable map key '[1]'. Keys must be nil, booleans, numbers, strings, bytes, or tuples and records of those.", 3, nil)
+}
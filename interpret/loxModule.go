@@ -0,0 +1,38 @@
+package interpret
+
+import (
+	"github.com/kljablon/golox/ast"
+	"github.com/kljablon/golox/utils"
+)
+
+// LoxModule is a namespace of natives and constants, such as math, whose
+// members are reached with the '.' operator.
+type LoxModule struct {
+	name    string
+	members map[string]any
+}
+
+func NewLoxModule(name string) *LoxModule {
+	return &LoxModule{name, make(map[string]any)}
+}
+
+// Define adds a constant or other value to the module.
+func (m *LoxModule) Define(name string, value any) {
+	m.members[name] = value
+}
+
+// DefineNative adds a native function to the module under its own name.
+func (m *LoxModule) DefineNative(native *NativeFunction) {
+	m.members[native.name] = native
+}
+
+func (m *LoxModule) get(name ast.Token) any {
+	if value, ok := m.members[name.Lexeme]; ok {
+		return value
+	}
+	panic(utils.NewRuntimeError(name, "Module '"+m.name+"' has no member '"+name.Lexeme+"'."))
+}
+
+func (m *LoxModule) toString() string {
+	return "<module " + m.name + ">"
+}
@@ -0,0 +1,9 @@
+package interpret
+
+import "github.com/kljablon/golox/ast"
+
+// LoxObject is implemented by runtime values that expose properties
+// through the '.' operator.
+type LoxObject interface {
+	get(name ast.Token) any
+}
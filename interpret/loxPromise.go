@@ -0,0 +1,232 @@
+package interpret
+
+import (
+	"github.com/kljablon/golox/ast"
+	"github.com/kljablon/golox/utils"
+)
+
+// LoxPromise is the result of calling an async function, which settles with
+// the function's return value. Awaiting a pending promise lets the event
+// loop run other callbacks until it settles.
+type LoxPromise struct {
+	loop      *eventLoop
+	settled   bool
+	value     any
+	callbacks []func()
+}
+
+func newPromise(loop *eventLoop) *LoxPromise {
+	return &LoxPromise{loop: loop}
+}
+
+// resolve settles the promise, queueing its callbacks on the event loop. A
+// promise resolved with another promise settles when that one does.
+func (p *LoxPromise) resolve(value any) {
+	if p.settled {
+		return
+	}
+	if other, ok := value.(*LoxPromise); ok && other.loop == p.loop {
+		other.onSettle(func() { p.resolve(other.value) })
+		return
+	}
+	p.settled = true
+	p.value = value
+	p.loop.queue = append(p.loop.queue, p.callbacks...)
+	p.callbacks = nil
+}
+
+// onSettle queues callback once the promise has settled.
+func (p *LoxPromise) onSettle(callback func()) {
+	if p.settled {
+		p.loop.queue = append(p.loop.queue, callback)
+		return
+	}
+	p.callbacks = append(p.callbacks, callback)
+}
+
+func (p *LoxPromise) get(name ast.Token) any {
+	switch name.Lexeme {
+	case "settled":
+		return p.settled
+	case "value":
+		return p.value
+	}
+	panic(utils.NewRuntimeError(name, "Undefined property '"+name.Lexeme+"'."))
+}
+
+func (p *LoxPromise) toString() string {
+	if p.settled {
+		return "<promise " + repr(p.value) + ">"
+	}
+	return "<promise pending>"
+}
+
+// coroutine runs the body of an async function on its own goroutine, taking
+// turns with whoever resumed it: the caller the first time, and the event
+// loop after every await. Only one of them runs at a time, so together they
+// act as the single task holding the scheduler's lock.
+type coroutine struct {
+	scheduler *scheduler
+	resumed   chan struct{}
+	paused    chan struct{}
+	running   bool
+	failure   any
+}
+
+// startAsync calls body on a new coroutine and returns the promise it
+// resolves. The call runs until body first awaits a pending promise.
+func (i *Interpreter) startAsync(body func(interpreter *Interpreter) any) *LoxPromise {
+	promise := newPromise(i.loop)
+	c := &coroutine{scheduler: i.scheduler, resumed: make(chan struct{}), paused: make(chan struct{})}
+	interpreter := *i
+	interpreter.coroutine = c
+
+	go func() {
+		defer func() {
+			c.failure = recover()
+			if c.running {
+				c.paused <- struct{}{}
+			}
+		}()
+		c.wait()
+		promise.resolve(body(&interpreter))
+	}()
+	c.resume()
+	return promise
+}
+
+// resume runs the coroutine until it pauses or finishes. Errors in the
+// coroutine are raised in the caller, which ends the script.
+func (c *coroutine) resume() {
+	select {
+	case c.resumed <- struct{}{}:
+	case <-c.scheduler.done:
+		c.scheduler.checkStopped()
+	}
+	<-c.paused
+	if failure := c.failure; failure != nil {
+		c.failure = nil
+		panic(failure)
+	}
+}
+
+// pause hands control back to whoever resumed the coroutine until it is
+// resumed again.
+func (c *coroutine) pause() {
+	c.running = false
+	c.paused <- struct{}{}
+	c.wait()
+	c.scheduler.checkStopped()
+}
+
+// wait blocks until the coroutine is resumed, unwinding it instead if the
+// script stops first.
+func (c *coroutine) wait() {
+	select {
+	case <-c.resumed:
+		c.running = true
+	case <-c.scheduler.done:
+		panic(taskStopped{})
+	}
+}
+
+// VisitExpr_Await returns the value of a promise, or any other value as is.
+// In an async function, a pending promise pauses the function until it
+// settles; in top-level code the event loop runs until it does.
+func (i *Interpreter) VisitExpr_Await(e ast.Expr_Await) any {
+	value := i.evaluate(e.Value)
+	promise, ok := value.(*LoxPromise)
+	if !ok {
+		return value
+	}
+	if promise.loop != i.loop {
+		panic(utils.NewRuntimeError(e.Keyword, "Can't await a promise from another task."))
+	}
+	if !promise.settled {
+		if c := i.coroutine; c != nil {
+			promise.onSettle(c.resume)
+			c.pause()
+		} else {
+			i.loop.run(i, func() bool { return promise.settled })
+			if !promise.settled {
+				panic(utils.NewRuntimeError(e.Keyword, "Awaited promise will never settle."))
+			}
+		}
+	}
+	return promise.value
+}
+
+// promisesOf checks that the promises among values belong to the current
+// task, whose event loop settles them.
+func promisesOf(interpreter *Interpreter, values []any) {
+	for _, value := range values {
+		if promise, ok := value.(*LoxPromise); ok && promise.loop != interpreter.loop {
+			panic(NativeError{"Can't use a promise from another task."})
+		}
+	}
+}
+
+func newPromiseModule() *LoxModule {
+	module := NewLoxModule("promise")
+	module.DefineNative(native("all", []ArgType{ITERABLE_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		elements := collect(arguments[0])
+		promisesOf(interpreter, elements)
+		result := newPromise(interpreter.loop)
+		values := make([]any, len(elements))
+		remaining := len(elements)
+		for index, element := range elements {
+			index := index
+			promise, ok := element.(*LoxPromise)
+			if !ok {
+				values[index] = element
+				remaining--
+				continue
+			}
+			promise.onSettle(func() {
+				values[index] = promise.value
+				remaining--
+				if remaining == 0 {
+					result.resolve(NewLoxList(values))
+				}
+			})
+		}
+		if remaining == 0 {
+			result.resolve(NewLoxList(values))
+		}
+		return result
+	}))
+	module.DefineNative(native("race", []ArgType{ITERABLE_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		elements := collect(arguments[0])
+		promisesOf(interpreter, elements)
+		result := newPromise(interpreter.loop)
+		for _, element := range elements {
+			promise, ok := element.(*LoxPromise)
+			if !ok {
+				result.resolve(element)
+				break
+			}
+			promise.onSettle(func() { result.resolve(promise.value) })
+		}
+		return result
+	}))
+	module.DefineNative(native("resolve", []ArgType{ANY_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		promisesOf(interpreter, arguments)
+		result := newPromise(interpreter.loop)
+		result.resolve(arguments[0])
+		return result
+	}))
+	module.DefineNative(NewNativeFunction("delay", 1, 2, []ArgType{NUMBER_ARG, ANY_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		result := newPromise(interpreter.loop)
+		var value any
+		if len(arguments) == 2 {
+			value = arguments[1]
+		}
+		resolve := native("resolve", []ArgType{ANY_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			result.resolve(arguments[0])
+			return nil
+		})
+		startTimer(false)(interpreter, []any{resolve, arguments[0], value})
+		return result
+	}))
+	return module
+}
@@ -0,0 +1,168 @@
+package interpret
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/kljablon/golox/ast"
+	"github.com/kljablon/golox/utils"
+)
+
+// LoxRange is the value of a..b (inclusive) or a..<b (exclusive). Ranges are
+// lazy: elements are computed on demand, so 0..1000000000 costs no more than
+// 0..1.
+type LoxRange struct {
+	start     float64
+	end       float64
+	step      float64
+	inclusive bool
+}
+
+// length returns how many elements the range yields.
+func (r *LoxRange) length() float64 {
+	span := (r.end - r.start) / r.step
+	if span < 0 {
+		return 0
+	}
+	if r.inclusive {
+		return math.Floor(span) + 1
+	}
+	return math.Ceil(span)
+}
+
+func (r *LoxRange) at(index float64) float64 {
+	return r.start + index*r.step
+}
+
+func (r *LoxRange) contains(value any) bool {
+	number, ok := value.(float64)
+	if !ok {
+		return false
+	}
+	position := (number - r.start) / r.step
+	return position == math.Trunc(position) && position >= 0 && position < r.length()
+}
+
+func (r *LoxRange) getIndex(bracket ast.Token, index any) any {
+	number, ok := index.(float64)
+	if !ok || number != math.Trunc(number) {
+		panic(utils.NewRuntimeError(bracket, "Index must be an integer."))
+	}
+	if number < 0 || number >= r.length() {
+		panic(utils.NewRuntimeError(bracket, "Index out of range."))
+	}
+	return r.at(number)
+}
+
+func (r *LoxRange) get(name ast.Token) any {
+	switch name.Lexeme {
+	case "length":
+		return r.length()
+	case "start":
+		return r.start
+	case "end":
+		return r.end
+	case "contains":
+		return native("contains", []ArgType{ANY_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			return r.contains(arguments[0])
+		})
+	case "step":
+		return native("step", []ArgType{NUMBER_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			step := arguments[0].(float64)
+			if step == 0 || math.IsNaN(step) || math.IsInf(step, 0) {
+				panic(NativeError{"step() argument must be a finite, non-zero number."})
+			}
+			return &LoxRange{r.start, r.end, step, r.inclusive}
+		})
+	}
+	panic(utils.NewRuntimeError(name, "Undefined property '"+name.Lexeme+"'."))
+}
+
+func (r *LoxRange) iterator() LoxIterator {
+	return &rangeIterator{r, 0, r.length()}
+}
+
+func (r *LoxRange) Equals(other any) bool {
+	o, ok := other.(*LoxRange)
+	return ok && *r == *o
+}
+
+func (r *LoxRange) toString() string {
+	operator := ".."
+	if !r.inclusive {
+		operator = "..<"
+	}
+	text := fmt.Sprintf("%v%s%v", r.start, operator, r.end)
+	if r.step != 1 {
+		return fmt.Sprintf("(%s).step(%v)", text, r.step)
+	}
+	return text
+}
+
+type rangeIterator struct {
+	rangeValue *LoxRange
+	index      float64
+	length     float64
+}
+
+func (r *rangeIterator) next() (any, bool) {
+	if r.index >= r.length {
+		return nil, false
+	}
+	value := r.rangeValue.at(r.index)
+	r.index++
+	return value, true
+}
+
+// slice returns the elements of a list, tuple or string at the positions
+// given by a range, as a value of the same kind.
+func slice(bracket ast.Token, object any, indexes *LoxRange) any {
+	var elements []any
+	switch object := object.(type) {
+	case *LoxList:
+		elements = object.elements
+	case *LoxTuple:
+		elements = object.elements
+	case string:
+		for _, r := range object {
+			elements = append(elements, string(r))
+		}
+	case *LoxBytes, *LoxBuffer:
+		for _, b := range toData(object) {
+			elements = append(elements, b)
+		}
+	default:
+		panic(utils.NewRuntimeError(bracket, "Only lists, tuples, strings and bytes can be sliced."))
+	}
+
+	result := []any{}
+	iterator := indexes.iterator()
+	for {
+		index, ok := iterator.next()
+		if !ok {
+			break
+		}
+		result = append(result, elements[toIndex(bracket, index, len(elements))])
+	}
+
+	switch object.(type) {
+	case *LoxTuple:
+		return NewLoxTuple(result)
+	case string:
+		text := ""
+		for _, element := range result {
+			text += element.(string)
+		}
+		return text
+	case *LoxBytes, *LoxBuffer:
+		data := make([]byte, len(result))
+		for index, element := range result {
+			data[index] = element.(byte)
+		}
+		if _, ok := object.(*LoxBuffer); ok {
+			return &LoxBuffer{data}
+		}
+		return NewLoxBytes(data)
+	}
+	return NewLoxList(result)
+}
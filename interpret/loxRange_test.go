@@ -0,0 +1,98 @@
+package interpret_test
+
+import "testing"
+
+func TestRangeLoops(t *testing.T) {
+	expectOutput(t, `
+for (i in 0..2) print i;
+for (i in 0..<2) print i;
+for (i in (1..10).step(4)) print i;
+for (i in (5..1).step(-2)) print i;
+`, "0\n1\n2\n0\n1\n1\n5\n9\n5\n3\n1\n", nil)
+}
+
+func TestRangeProperties(t *testing.T) {
+	expectOutput(t, `
+var r = 1..10;
+print r;
+print r.length;
+print r[2];
+print r.start;
+print r.end;
+print r.contains(5);
+print r.contains(11);
+print r.contains(2.5);
+print r.step(3);
+print (5..1).length;
+print (0..<0).length;
+print (0..<1000000).contains(999999);
+`, "1..10\n10\n3\n1\n10\ntrue\nfalse\nfalse\n(1..10).step(3)\n0\n0\ntrue\n", nil)
+}
+
+func TestRangeSlices(t *testing.T) {
+	expectOutput(t, `
+print [1, 2, 3, 4][1..2];
+print [1, 2, 3, 4][1..<3];
+print [1, 2, 3, 4][(3..0).step(-1)];
+print (1, 2, 3)[0..<2];
+print "héllo"[1..3];
+print [1, 2][0..<0];
+`, "[2, 3]\n[2, 3]\n[4, 3, 2, 1]\n(1, 2)\néll\n[]\n", nil)
+}
+
+func TestRangeErrors(t *testing.T) {
+	expectError(t, `
+var r = 1.."a";
+`, "Range bounds must be numbers.", 2, nil)
+	expectError(t, `
+print (1..3).step(0);
+`, "step() argument must be a finite, non-zero number.", 2, nil)
+	expectError(t, `
+print [1, 2][0..2];
+`, "Index out of range.", 2, nil)
+}
+
+func TestRangeSteps(t *testing.T) {
+	expectOutput(t, `
+for (i in (0..1).step(0.25)) print i;
+print (0..<1).step(0.5).length;
+print (10..0).step(3).length;
+print (0..10).step(-1).length;
+for (i in (0..10).step(100)) print i;
+print (0..10).step(3)[3];
+print (0..10).step(3).contains(9);
+print (0..10).step(3).contains(10);
+print (0..1).contains(0/0);
+`, "0\n0.25\n0.5\n0.75\n1\n2\n0\n0\n0\n9\ntrue\nfalse\nfalse\n", nil)
+}
+
+func TestRangeStepSlices(t *testing.T) {
+	expectOutput(t, `
+print [1, 2, 3, 4, 5][(0..4).step(2)];
+print [1, 2, 3, 4, 5][(4..<0).step(-2)];
+print "abcdef"[(5..0).step(-5)];
+print [1, 2][(0..1).step(5)];
+print [1, 2][(1..0).step(1)];
+`, "[1, 3, 5]\n[5, 3]\nfa\n[1]\n[]\n", nil)
+	expectError(t, `
+print [1, 2, 3][(0..2).step(0.5)];
+`, "Index must be an integer.", 2, nil)
+}
+
+func TestRangeNaN(t *testing.T) {
+	expectError(t, `
+for (i in 0..(0/0)) print i;
+`, "Range bounds can't be NaN.", 2, nil)
+	expectError(t, `
+var r = (0/0)..<3;
+`, "Range bounds can't be NaN.", 2, nil)
+	expectError(t, `
+print [1, 2, 3][0..(0/0)];
+`, "Range bounds can't be NaN.", 2, nil)
+	expectError(t, `
+print (1..3).step(0/0);
+`, "step() argument must be a finite, non-zero number.", 2, nil)
+	expectError(t, `
+print (1..3).step(1/0);
+`, "step() argument must be a finite, non-zero number.", 2, nil)
+}
@@ -0,0 +1,62 @@
+package interpret
+
+import (
+	"strings"
+
+	"github.com/kljablon/golox/ast"
+	"github.com/kljablon/golox/utils"
+)
+
+// LoxRecord is the value bound to the name of a record declaration. Calling
+// it constructs an instance with one argument per field.
+type LoxRecord struct {
+	name   string
+	fields []string
+}
+
+func (r *LoxRecord) arity() (int, int) {
+	return len(r.fields), len(r.fields)
+}
+
+func (r *LoxRecord) call(interpreter *Interpreter, arguments []any) any {
+	return &LoxRecordInstance{r, append([]any{}, arguments...)}
+}
+
+func (r *LoxRecord) toString() string {
+	return "<record " + r.name + ">"
+}
+
+// LoxRecordInstance holds the field values of a record. Fields can't be
+// reassigned, and two instances are equal when they come from the same
+// record declaration and all their fields are equal.
+type LoxRecordInstance struct {
+	record *LoxRecord
+	values []any
+}
+
+func (r *LoxRecordInstance) get(name ast.Token) any {
+	for i, field := range r.record.fields {
+		if field == name.Lexeme {
+			return r.values[i]
+		}
+	}
+	if name.Lexeme == "toString" {
+		return native("toString", nil, func(interpreter *Interpreter, arguments []any) any {
+			return r.toString()
+		})
+	}
+	panic(utils.NewRuntimeError(name, "Undefined property '"+name.Lexeme+"'."))
+}
+
+func (r *LoxRecordInstance) Equals(other any) bool {
+	o, ok := other.(*LoxRecordInstance)
+	return ok && r.record == o.record && elementsEqual(r.values, o.values)
+}
+
+func (r *LoxRecordInstance) toString() string {
+	parts := make([]string, len(r.values))
+	for i, value := range r.values {
+		parts[i] = r.record.fields[i] + ": " + repr(value)
+	}
+	return r.record.name + "(" + strings.Join(parts, ", ") + ")"
+}
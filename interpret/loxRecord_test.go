@@ -0,0 +1,33 @@
+package interpret_test
+
+import "testing"
+
+func TestRecord(t *testing.T) {
+	expectOutput(t, `
+record Point(x, y);
+record Other(x, y);
+var p = Point(1, 2);
+print p;
+print p.x + p.y;
+print p.toString();
+print p == Point(1, 2);
+print p == Point(1, 3);
+print p == Other(1, 2);
+print Point;
+`, "Point(x: 1, y: 2)\n3\nPoint(x: 1, y: 2)\ntrue\nfalse\nfalse\n<record Point>\n", nil)
+}
+
+func TestRecordIsImmutable(t *testing.T) {
+	expectError(t, `
+record P(x);
+var p = P(1);
+p.x = 2;
+`, "Can't assign to field 'x' of immutable record P.", 4, nil)
+}
+
+func TestRecordArity(t *testing.T) {
+	expectError(t, `
+record P(x);
+P(1, 2);
+`, "Expected 1 arguments but got 2.", 3, nil)
+}
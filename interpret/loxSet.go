@@ -0,0 +1,175 @@
+package interpret
+
+import (
+	"strings"
+
+	"github.com/kljablon/golox/ast"
+	"github.com/kljablon/golox/utils"
+)
+
+// LoxSet is an unordered collection of distinct hashable values. Members are
+// compared with utils.IsEqual, like map keys, and iteration follows insertion
+// order.
+type LoxSet struct {
+	elements []any
+	members  map[any]bool
+}
+
+func NewLoxSet() *LoxSet {
+	return &LoxSet{
+		elements: []any{},
+		members:  make(map[any]bool),
+	}
+}
+
+// newSet implements the Set() constructor: Set() is the empty set and
+// Set(iterable) collects the elements of a list, tuple, map or set.
+func newSet(interpreter *Interpreter, arguments []any) any {
+	set := NewLoxSet()
+	if len(arguments) == 0 {
+		return set
+	}
+	iterator, _ := iteratorFor(arguments[0])
+	defer releaseIterator(iterator)
+	for {
+		value, ok := iterator.next()
+		if !ok {
+			break
+		}
+		set.add(value)
+	}
+	return set
+}
+
+// checkSetElement returns the hash key of value, raising an error if it
+// can't be a set member.
+func checkSetElement(value any) any {
+	hashed, ok := hashKey(value)
+	if !ok {
+		panic(NativeError{"Unhashable set element '" + stringify(value) + "'. Elements must be nil, booleans, numbers, strings, bytes, or tuples and records of those."})
+	}
+	return hashed
+}
+
+func (s *LoxSet) add(value any) {
+	hashed := checkSetElement(value)
+	if !s.members[hashed] {
+		s.members[hashed] = true
+		s.elements = append(s.elements, value)
+	}
+}
+
+func (s *LoxSet) contains(value any) bool {
+	hashed, ok := hashKey(value)
+	return ok && s.members[hashed]
+}
+
+func (s *LoxSet) remove(value any) bool {
+	if !s.contains(value) {
+		return false
+	}
+	hashed, _ := hashKey(value)
+	delete(s.members, hashed)
+	for i, element := range s.elements {
+		if utils.IsEqual(element, value) {
+			s.elements = append(s.elements[:i], s.elements[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+func (s *LoxSet) union(other *LoxSet) *LoxSet {
+	result := NewLoxSet()
+	for _, element := range s.elements {
+		result.add(element)
+	}
+	for _, element := range other.elements {
+		result.add(element)
+	}
+	return result
+}
+
+func (s *LoxSet) intersection(other *LoxSet) *LoxSet {
+	result := NewLoxSet()
+	for _, element := range s.elements {
+		if other.contains(element) {
+			result.add(element)
+		}
+	}
+	return result
+}
+
+func (s *LoxSet) difference(other *LoxSet) *LoxSet {
+	result := NewLoxSet()
+	for _, element := range s.elements {
+		if !other.contains(element) {
+			result.add(element)
+		}
+	}
+	return result
+}
+
+// setOperation wraps one of the binary set operations as a bound method.
+func (s *LoxSet) setOperation(name string, operation func(other *LoxSet) *LoxSet) *NativeFunction {
+	return native(name, []ArgType{SET_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		return operation(arguments[0].(*LoxSet))
+	})
+}
+
+func (s *LoxSet) get(name ast.Token) any {
+	switch name.Lexeme {
+	case "length":
+		return float64(len(s.elements))
+	case "add":
+		return native("add", []ArgType{ANY_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			s.add(arguments[0])
+			return nil
+		})
+	case "remove":
+		return native("remove", []ArgType{ANY_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			return s.remove(arguments[0])
+		})
+	case "contains":
+		return native("contains", []ArgType{ANY_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			return s.contains(arguments[0])
+		})
+	case "union":
+		return s.setOperation("union", s.union)
+	case "intersection":
+		return s.setOperation("intersection", s.intersection)
+	case "difference":
+		return s.setOperation("difference", s.difference)
+	}
+	panic(utils.NewRuntimeError(name, "Undefined property '"+name.Lexeme+"'."))
+}
+
+// iterator works on a snapshot, so the set may be modified inside the loop.
+func (s *LoxSet) iterator() LoxIterator {
+	return &sliceIterator{elements: append([]any{}, s.elements...)}
+}
+
+// Equals reports whether other is a set with exactly the same members.
+func (s *LoxSet) Equals(other any) bool {
+	o, ok := other.(*LoxSet)
+	if !ok || len(s.elements) != len(o.elements) {
+		return false
+	}
+	for _, element := range s.elements {
+		if !o.contains(element) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *LoxSet) toString() string {
+	if len(s.elements) == 0 {
+		return "Set()"
+	}
+	parts := make([]string, len(s.elements))
+	for i, element := range s.elements {
+		parts[i] = repr(element)
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
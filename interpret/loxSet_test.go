@@ -0,0 +1,71 @@
+package interpret_test
+
+import "testing"
+
+func TestSet(t *testing.T) {
+	expectOutput(t, `
+var s = Set([1, 2, 2, 3]);
+print s;
+print s.length;
+s.add(4);
+s.add(1);
+print s.contains(4);
+print s.remove(2);
+print s.remove(2);
+print s;
+print Set();
+print Set({"a": 1, "b": 2});
+print Set((1, 1));
+for (x in Set(["x", "y"])) print x;
+`, "{1, 2, 3}\n3\ntrue\ntrue\nfalse\n{1, 3, 4}\nSet()\n{\"a\", \"b\"}\n{1}\nx\ny\n", nil)
+}
+
+func TestSetOperations(t *testing.T) {
+	expectOutput(t, `
+var a = Set([1, 2]);
+var b = Set([2, 3]);
+print a.union(b);
+print a.intersection(b);
+print a.difference(b);
+print a == Set([2, 1]);
+print a == Set([1]);
+`, "{1, 2, 3}\n{2}\n{1}\ntrue\nfalse\n", nil)
+}
+
+func TestSetUnhashableElement(t *testing.T) {
+	expectError(t, `
+var s = Set();
+s.add([1]);
+`, "Unhashable set element '[1]'. Elements must be nil, booleans, numbers, strings, bytes, or tuples and records of those.", 3, nil)
+}
+
+func TestSetAndMapHashValues(t *testing.T) {
+	expectOutput(t, `
+record P(x, y);
+var s = Set([(1, 2), (1, 2), P(1, 2), P(1, 2), bytes("k")]);
+print s.length;
+print s.contains(bytes("k"));
+print Set([(1, 2), (2, 1)]).length;
+var m = {};
+m[(1, "a")] = "tuple";
+m[P(1, 2)] = "record";
+print m[(1, "a")];
+print m[P(1, 2)];
+print m.length;
+`, "3\ntrue\n2\ntuple\nrecord\n2\n", nil)
+}
+
+func TestSetLiteral(t *testing.T) {
+	expectOutput(t, `
+var rest = [3, 4];
+var s = {1, 2, ...rest, 1};
+print s;
+print s.contains(4);
+print {(1, 2)};
+print {};
+print {"a": 1};
+`, "{1, 2, 3, 4}\ntrue\n{(1, 2)}\n{}\n{\"a\": 1}\n", nil)
+	expectError(t, `
+print {[1]};
+`, "Unhashable set element '[1]'. Elements must be nil, booleans, numbers, strings, bytes, or tuples and records of those.", 2, nil)
+}
@@ -0,0 +1,143 @@
+package interpret
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kljablon/golox/ast"
+	"github.com/kljablon/golox/utils"
+)
+
+// Lox strings are plain Go strings. Lengths, indexes and offsets below are
+// all counted in Unicode code points rather than bytes.
+
+// maxLength caps the size in bytes of the strings and buffers natives build
+// from a count, so a huge count raises an error instead of exhausting memory.
+const maxLength = 1 << 30
+
+func stringIndex(bracket ast.Token, s string, index any) any {
+	runes := []rune(s)
+	return string(runes[toIndex(bracket, index, len(runes))])
+}
+
+// toBound checks that an integer argument lies within [0, length], so it can
+// be used for either end of a half-open range.
+func toBound(value any, length int) int {
+	number := value.(float64)
+	if number < 0 || number > float64(length) {
+		panic(NativeError{"Bound out of range."})
+	}
+	return int(number)
+}
+
+// runeOffset converts a byte offset within s into a code point offset.
+func runeOffset(s string, byteOffset int) float64 {
+	if byteOffset < 0 {
+		return -1
+	}
+	return float64(utf8.RuneCountInString(s[:byteOffset]))
+}
+
+func stringGet(s string, name ast.Token) any {
+	switch name.Lexeme {
+	case "length":
+		return float64(utf8.RuneCountInString(s))
+	case "substring":
+		return NewNativeFunction("substring", 1, 2, []ArgType{INTEGER_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			runes := []rune(s)
+			start := toBound(arguments[0], len(runes))
+			end := len(runes)
+			if len(arguments) == 2 {
+				end = toBound(arguments[1], len(runes))
+			}
+			if start > end {
+				panic(NativeError{"substring() start is after end."})
+			}
+			return string(runes[start:end])
+		})
+	case "split":
+		return native("split", []ArgType{STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			parts := strings.Split(s, arguments[0].(string))
+			elements := make([]any, len(parts))
+			for i, part := range parts {
+				elements[i] = part
+			}
+			return NewLoxList(elements)
+		})
+	case "join":
+		return native("join", []ArgType{ITERABLE_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			iterator, _ := iteratorFor(arguments[0])
+			parts := []string{}
+			for {
+				value, ok := iterator.next()
+				if !ok {
+					break
+				}
+				parts = append(parts, stringify(value))
+			}
+			return strings.Join(parts, s)
+		})
+	case "trim":
+		return native("trim", nil, func(interpreter *Interpreter, arguments []any) any {
+			return strings.TrimSpace(s)
+		})
+	case "replace":
+		return native("replace", []ArgType{STRING_ARG, STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			return strings.ReplaceAll(s, arguments[0].(string), arguments[1].(string))
+		})
+	case "startsWith":
+		return native("startsWith", []ArgType{STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			return strings.HasPrefix(s, arguments[0].(string))
+		})
+	case "endsWith":
+		return native("endsWith", []ArgType{STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			return strings.HasSuffix(s, arguments[0].(string))
+		})
+	case "contains":
+		return native("contains", []ArgType{STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			return strings.Contains(s, arguments[0].(string))
+		})
+	case "indexOf":
+		return native("indexOf", []ArgType{STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			return runeOffset(s, strings.Index(s, arguments[0].(string)))
+		})
+	case "upper":
+		return native("upper", nil, func(interpreter *Interpreter, arguments []any) any {
+			return strings.ToUpper(s)
+		})
+	case "lower":
+		return native("lower", nil, func(interpreter *Interpreter, arguments []any) any {
+			return strings.ToLower(s)
+		})
+	case "repeat":
+		return native("repeat", []ArgType{INTEGER_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			count := arguments[0].(float64)
+			if count < 0 {
+				panic(NativeError{"repeat() count must not be negative."})
+			}
+			if s == "" {
+				return ""
+			}
+			if count*float64(len(s)) > maxLength {
+				panic(NativeError{"repeat() result would be too long."})
+			}
+			return strings.Repeat(s, int(count))
+		})
+	}
+	panic(utils.NewRuntimeError(name, "Undefined property '"+name.Lexeme+"'."))
+}
+
+// stringIterator yields the code points of a string as one-character strings.
+type stringIterator struct {
+	runes []rune
+	index int
+}
+
+func (s *stringIterator) next() (any, bool) {
+	if s.index >= len(s.runes) {
+		return nil, false
+	}
+	value := string(s.runes[s.index])
+	s.index++
+	return value, true
+}
@@ -0,0 +1,53 @@
+package interpret_test
+
+import "testing"
+
+func TestStringIndexingCountsCodePoints(t *testing.T) {
+	expectOutput(t, `
+var s = "héllo wörld";
+print s[1];
+print s.length;
+print s.substring(1, 4);
+print s.indexOf("w");
+print s.indexOf("z");
+for (c in "añb") print c;
+`, "é\n11\néll\n6\n-1\na\nñ\nb\n", nil)
+}
+
+func TestStringMethods(t *testing.T) {
+	expectOutput(t, `
+var s = "héllo wörld";
+print s.split(" ");
+print "-".join(["a", "b", "c"]);
+print "  pad ".trim();
+print s.replace("l", "L");
+print s.startsWith("hé");
+print s.endsWith("x");
+print s.contains("wö");
+print s.upper();
+print "ABC".lower();
+print "ab".repeat(3);
+`, "[\"héllo\", \"wörld\"]\na-b-c\npad\nhéLLo wörLd\ntrue\nfalse\ntrue\nHÉLLO WÖRLD\nabc\nababab\n", nil)
+}
+
+func TestStringErrors(t *testing.T) {
+	expectError(t, `
+var s = "abc";
+s[0] = "x";
+`, "Strings are immutable.", 3, nil)
+	expectError(t, `
+print "abc"[3];
+`, "Index out of range.", 2, nil)
+	expectError(t, `
+print "abc".substring(2, 1);
+`, "substring() start is after end.", 2, nil)
+}
+
+func TestStringRepeatLimit(t *testing.T) {
+	expectOutput(t, `
+print "".repeat(1000000000).length;
+`, "0\n", nil)
+	expectError(t, `
+print "ab".repeat(1000000000);
+`, "repeat() result would be too long.", 2, nil)
+}
@@ -0,0 +1,204 @@
+package interpret
+
+import (
+	"github.com/kljablon/golox/ast"
+	"github.com/kljablon/golox/utils"
+)
+
+// LoxMutex lets one task at a time run a section of code that uses shared
+// state. Like the rest of the sync values it only matters between statements
+// of different tasks, since a single statement never runs interleaved.
+type LoxMutex struct {
+	scheduler *scheduler
+	locked    bool
+	waiters   []*waiter
+}
+
+func newMutex(interpreter *Interpreter, arguments []any) any {
+	return &LoxMutex{scheduler: interpreter.scheduler}
+}
+
+// popParked takes the first waiter from queue that hasn't already been woken.
+func popParked(queue *[]*waiter) (*waiter, bool) {
+	for len(*queue) > 0 {
+		first := (*queue)[0]
+		*queue = (*queue)[1:]
+		if !first.fired {
+			return first, true
+		}
+	}
+	return nil, false
+}
+
+func (m *LoxMutex) lock() {
+	if !m.locked {
+		m.locked = true
+		return
+	}
+	// unlock hands the mutex straight to the task it wakes.
+	w := newWaiter()
+	m.waiters = append(m.waiters, w)
+	m.scheduler.park(w, nil)
+}
+
+func (m *LoxMutex) unlock() {
+	if !m.locked {
+		panic(NativeError{"Mutex is not locked."})
+	}
+	if w, ok := popParked(&m.waiters); ok {
+		m.scheduler.wake(w, 0, nil, true)
+		return
+	}
+	m.locked = false
+}
+
+func (m *LoxMutex) get(name ast.Token) any {
+	switch name.Lexeme {
+	case "lock":
+		return native("lock", nil, func(interpreter *Interpreter, arguments []any) any {
+			m.lock()
+			return nil
+		})
+	case "unlock":
+		return native("unlock", nil, func(interpreter *Interpreter, arguments []any) any {
+			m.unlock()
+			return nil
+		})
+	case "withLock":
+		return native("withLock", []ArgType{CALLABLE_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			m.lock()
+			defer m.unlock()
+			return callFunction(interpreter, arguments[0].(LoxCallable), nil)
+		})
+	case "locked":
+		return m.locked
+	}
+	panic(utils.NewRuntimeError(name, "Undefined property '"+name.Lexeme+"'."))
+}
+
+func (m *LoxMutex) toString() string {
+	if m.locked {
+		return "<mutex locked>"
+	}
+	return "<mutex>"
+}
+
+// LoxWaitGroup waits for a number of tasks to finish: add counts them up,
+// done counts them down, and wait returns once the count is zero.
+type LoxWaitGroup struct {
+	scheduler *scheduler
+	count     int
+	waiters   []*waiter
+}
+
+func newWaitGroup(interpreter *Interpreter, arguments []any) any {
+	return &LoxWaitGroup{scheduler: interpreter.scheduler}
+}
+
+func (g *LoxWaitGroup) add(delta int) {
+	if g.count+delta < 0 {
+		panic(NativeError{"WaitGroup counter can't go below zero."})
+	}
+	g.count += delta
+	if g.count == 0 {
+		for _, w := range g.waiters {
+			g.scheduler.wake(w, 0, nil, true)
+		}
+		g.waiters = nil
+	}
+}
+
+func (g *LoxWaitGroup) get(name ast.Token) any {
+	switch name.Lexeme {
+	case "add":
+		return NewNativeFunction("add", 0, 1, []ArgType{INTEGER_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			delta := 1
+			if len(arguments) == 1 {
+				delta = int(arguments[0].(float64))
+			}
+			g.add(delta)
+			return nil
+		})
+	case "done":
+		return native("done", nil, func(interpreter *Interpreter, arguments []any) any {
+			g.add(-1)
+			return nil
+		})
+	case "wait":
+		return native("wait", nil, func(interpreter *Interpreter, arguments []any) any {
+			if g.count > 0 {
+				w := newWaiter()
+				g.waiters = append(g.waiters, w)
+				g.scheduler.park(w, nil)
+			}
+			return nil
+		})
+	case "count":
+		return float64(g.count)
+	}
+	panic(utils.NewRuntimeError(name, "Undefined property '"+name.Lexeme+"'."))
+}
+
+func (g *LoxWaitGroup) toString() string {
+	return "<waitgroup>"
+}
+
+// LoxAtomic holds a value that tasks update with single operations, so that
+// reading and writing it back can't be interleaved with another task.
+type LoxAtomic struct {
+	value any
+}
+
+// newAtomic implements Atomic(): the value starts as 0 unless one is given.
+func newAtomic(interpreter *Interpreter, arguments []any) any {
+	if len(arguments) == 1 {
+		return &LoxAtomic{arguments[0]}
+	}
+	return &LoxAtomic{0.0}
+}
+
+func (a *LoxAtomic) get(name ast.Token) any {
+	switch name.Lexeme {
+	case "get":
+		return native("get", nil, func(interpreter *Interpreter, arguments []any) any {
+			return a.value
+		})
+	case "set":
+		return native("set", []ArgType{ANY_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			a.value = arguments[0]
+			return nil
+		})
+	case "add":
+		return NewNativeFunction("add", 0, 1, []ArgType{NUMBER_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			number, ok := a.value.(float64)
+			if !ok {
+				panic(NativeError{"Can only add to an atomic number."})
+			}
+			delta := 1.0
+			if len(arguments) == 1 {
+				delta = arguments[0].(float64)
+			}
+			a.value = number + delta
+			return a.value
+		})
+	case "swap":
+		return native("swap", []ArgType{ANY_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			old := a.value
+			a.value = arguments[0]
+			return old
+		})
+	case "compareAndSwap":
+		return native("compareAndSwap", []ArgType{ANY_ARG, ANY_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			if !utils.IsEqual(a.value, arguments[0]) {
+				return false
+			}
+			a.value = arguments[1]
+			return true
+		})
+	}
+	panic(utils.NewRuntimeError(name, "Undefined property '"+name.Lexeme+"'."))
+}
+
+func (a *LoxAtomic) toString() string {
+	return "Atomic(" + repr(a.value) + ")"
+}
@@ -0,0 +1,61 @@
+package interpret
+
+import (
+	"strings"
+
+	"github.com/kljablon/golox/ast"
+	"github.com/kljablon/golox/utils"
+)
+
+// LoxTuple is a fixed-size, immutable sequence. Two tuples are equal when
+// their elements are pairwise equal.
+type LoxTuple struct {
+	elements []any
+}
+
+func NewLoxTuple(elements []any) *LoxTuple {
+	return &LoxTuple{elements}
+}
+
+func (t *LoxTuple) get(name ast.Token) any {
+	if name.Lexeme == "length" {
+		return float64(len(t.elements))
+	}
+	panic(utils.NewRuntimeError(name, "Undefined property '"+name.Lexeme+"'."))
+}
+
+func (t *LoxTuple) getIndex(bracket ast.Token, index any) any {
+	return t.elements[toIndex(bracket, index, len(t.elements))]
+}
+
+func (t *LoxTuple) iterator() LoxIterator {
+	return &sliceIterator{elements: t.elements}
+}
+
+func (t *LoxTuple) Equals(other any) bool {
+	o, ok := other.(*LoxTuple)
+	return ok && elementsEqual(t.elements, o.elements)
+}
+
+func (t *LoxTuple) toString() string {
+	parts := make([]string, len(t.elements))
+	for i, element := range t.elements {
+		parts[i] = repr(element)
+	}
+	if len(parts) == 1 {
+		return "(" + parts[0] + ",)"
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+func elementsEqual(a []any, b []any) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !utils.IsEqual(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,25 @@
+package interpret_test
+
+import "testing"
+
+func TestTuple(t *testing.T) {
+	expectOutput(t, `
+var t = (1, "a", (2, 3));
+print t;
+print t[2];
+print t.length;
+print ();
+print (1,);
+print (1);
+print (1, 2) == (1, 2);
+print (1, 2) != (1, 3);
+for (x in (4, 5)) print x;
+`, "(1, \"a\", (2, 3))\n(2, 3)\n3\n()\n(1,)\n1\ntrue\ntrue\n4\n5\n", nil)
+}
+
+func TestTupleIsImmutable(t *testing.T) {
+	expectError(t, `
+var t = (1, 2);
+t[0] = 3;
+`, "Tuples are immutable.", 3, nil)
+}
@@ -0,0 +1,63 @@
+package interpret
+
+import "math"
+
+// unaryMath wraps a float64 function from the math package as a native.
+func unaryMath(name string, function func(float64) float64) *NativeFunction {
+	return native(name, []ArgType{NUMBER_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		return function(arguments[0].(float64))
+	})
+}
+
+// binaryMath wraps a two-argument float64 function as a native.
+func binaryMath(name string, function func(float64, float64) float64) *NativeFunction {
+	return native(name, []ArgType{NUMBER_ARG, NUMBER_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		return function(arguments[0].(float64), arguments[1].(float64))
+	})
+}
+
+// extremum implements the variadic min and max natives.
+func extremum(name string, pick func(float64, float64) float64) *NativeFunction {
+	return NewNativeFunction(name, 1, -1, []ArgType{NUMBER_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		result := arguments[0].(float64)
+		for _, argument := range arguments[1:] {
+			result = pick(result, argument.(float64))
+		}
+		return result
+	})
+}
+
+func newMathModule() *LoxModule {
+	module := NewLoxModule("math")
+
+	module.Define("pi", math.Pi)
+	module.Define("e", math.E)
+	module.Define("inf", math.Inf(1))
+	module.Define("nan", math.NaN())
+
+	module.DefineNative(unaryMath("sqrt", math.Sqrt))
+	module.DefineNative(binaryMath("pow", math.Pow))
+	module.DefineNative(unaryMath("floor", math.Floor))
+	module.DefineNative(unaryMath("ceil", math.Ceil))
+	module.DefineNative(unaryMath("round", math.Round))
+	module.DefineNative(unaryMath("abs", math.Abs))
+	module.DefineNative(extremum("min", math.Min))
+	module.DefineNative(extremum("max", math.Max))
+
+	module.DefineNative(unaryMath("sin", math.Sin))
+	module.DefineNative(unaryMath("cos", math.Cos))
+	module.DefineNative(unaryMath("tan", math.Tan))
+	module.DefineNative(unaryMath("asin", math.Asin))
+	module.DefineNative(unaryMath("acos", math.Acos))
+	module.DefineNative(unaryMath("atan", math.Atan))
+	module.DefineNative(binaryMath("atan2", math.Atan2))
+
+	module.DefineNative(unaryMath("log", math.Log))
+	module.DefineNative(unaryMath("log10", math.Log10))
+	module.DefineNative(unaryMath("exp", math.Exp))
+
+	module.DefineNative(native("isNaN", []ArgType{NUMBER_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		return math.IsNaN(arguments[0].(float64))
+	}))
+	return module
+}
@@ -0,0 +1,34 @@
+package interpret_test
+
+import "testing"
+
+func TestMathModule(t *testing.T) {
+	expectOutput(t, `
+print math.sqrt(16);
+print math.pow(2, 10);
+print math.floor(1.5);
+print math.ceil(1.2);
+print math.round(2.5);
+print math.abs(-3);
+print math.min(3, 1, 2);
+print math.max(3, 1, 2);
+print math.isNaN(math.nan);
+print math.inf;
+print math.atan2(1, 1) * 4 == math.pi;
+print math.log(math.e);
+print math.log10(1000);
+print math;
+`, "4\n1024\n1\n2\n3\n3\n1\n3\ntrue\n+Inf\ntrue\n1\n3\n<module math>\n", nil)
+}
+
+func TestMathErrors(t *testing.T) {
+	expectError(t, `
+math.sqrt("a");
+`, "Argument 1 to sqrt() must be a number.", 2, nil)
+	expectError(t, `
+math.nope;
+`, "Module 'math' has no member 'nope'.", 2, nil)
+	expectError(t, `
+math.min();
+`, "Expected at least 1 arguments but got 0.", 2, nil)
+}
@@ -1,17 +1,181 @@
 package interpret
 
-import "time"
+import (
+	"fmt"
+	"math"
+)
 
-type ClockFunc struct{}
+// ArgType is the kind of value a native function accepts for an argument.
+type ArgType int
 
-func (c *ClockFunc) arity() int {
-	return 0
+const (
+	ANY_ARG ArgType = iota
+	NUMBER_ARG
+	INTEGER_ARG
+	STRING_ARG
+	BOOL_ARG
+	LIST_ARG
+	MAP_ARG
+	SET_ARG
+	CALLABLE_ARG
+	ITERABLE_ARG
+	DATA_ARG
+)
+
+var argTypeNames = []string{
+	"any value", "a number", "an integer", "a string", "a boolean",
+	"a list", "a map", "a set", "a function", "iterable",
+	"a string or bytes",
+}
+
+func (a ArgType) matches(value any) bool {
+	switch a {
+	case NUMBER_ARG:
+		_, ok := value.(float64)
+		return ok
+	case INTEGER_ARG:
+		number, ok := value.(float64)
+		return ok && number == math.Trunc(number) && !math.IsInf(number, 0)
+	case STRING_ARG:
+		_, ok := value.(string)
+		return ok
+	case BOOL_ARG:
+		_, ok := value.(bool)
+		return ok
+	case LIST_ARG:
+		_, ok := value.(*LoxList)
+		return ok
+	case MAP_ARG:
+		_, ok := value.(*LoxMap)
+		return ok
+	case SET_ARG:
+		_, ok := value.(*LoxSet)
+		return ok
+	case CALLABLE_ARG:
+		_, ok := value.(LoxCallable)
+		return ok
+	case ITERABLE_ARG:
+		_, ok := iteratorFor(value)
+		return ok
+	case DATA_ARG:
+		switch value.(type) {
+		case string, *LoxBytes, *LoxBuffer:
+			return true
+		}
+		return false
+	}
+	return true
 }
 
-func (c *ClockFunc) call(interpreter Interpreter, arguments []any) any {
-	return float64(time.Now().UnixMilli())
+// NativeError is raised (with panic) by native functions to report a Lox
+// runtime error. Natives don't know where they were called from, so
+// VisitExpr_Call turns it into a utils.RuntimeError at the call site.
+type NativeError struct {
+	Message string
 }
 
-func (c *ClockFunc) toString() string {
-	return "<native fn>"
+func (n NativeError) Error() string {
+	return n.Message
+}
+
+// NativeFn is the Go implementation of a native function. Its arguments have
+// already been checked against the function's arity and parameter types.
+type NativeFn func(interpreter *Interpreter, arguments []any) any
+
+// callFunction calls a Lox function handed to a native, such as a callback,
+// checking the number of arguments the way a call expression would.
+func callFunction(interpreter *Interpreter, function LoxCallable, arguments []any) any {
+	minArity, maxArity := function.arity()
+	if len(arguments) < minArity || (maxArity >= 0 && len(arguments) > maxArity) {
+		panic(NativeError{arityMessage(minArity, maxArity, len(arguments))})
+	}
+	return function.call(interpreter, arguments)
+}
+
+// NativeFunction is a function implemented in Go.
+type NativeFunction struct {
+	name     string
+	minArity int
+	maxArity int
+	params   []ArgType
+	function NativeFn
+}
+
+// NewNativeFunction creates a native taking between minArity and maxArity
+// arguments; a maxArity of -1 means there is no upper limit. Each argument is
+// checked against the matching entry of params, and arguments past the end
+// of params against its last entry. A nil params accepts anything.
+func NewNativeFunction(name string, minArity int, maxArity int, params []ArgType, function NativeFn) *NativeFunction {
+	return &NativeFunction{name, minArity, maxArity, params, function}
+}
+
+// native is shorthand for a native with exactly one argument per entry in
+// params.
+func native(name string, params []ArgType, function NativeFn) *NativeFunction {
+	return NewNativeFunction(name, len(params), len(params), params, function)
+}
+
+func (n *NativeFunction) arity() (int, int) {
+	return n.minArity, n.maxArity
+}
+
+func (n *NativeFunction) call(interpreter *Interpreter, arguments []any) any {
+	for index, argument := range arguments {
+		if len(n.params) == 0 {
+			break
+		}
+		expected := n.params[min(index, len(n.params)-1)]
+		if !expected.matches(argument) {
+			panic(NativeError{fmt.Sprintf("Argument %d to %s() must be %s.", index+1, n.name, argTypeNames[expected])})
+		}
+	}
+	return n.function(interpreter, arguments)
+}
+
+func (n *NativeFunction) toString() string {
+	return "<native fn " + n.name + ">"
+}
+
+// DefineNative makes a native function available to scripts as a global.
+func (i *Interpreter) DefineNative(native *NativeFunction) {
+	i.globals.define(native.name, native)
+}
+
+// DefineGlobal makes a value available to scripts as a global variable.
+func (i *Interpreter) DefineGlobal(name string, value any) {
+	i.globals.define(name, value)
+}
+
+func defineBuiltins(i *Interpreter) {
+	i.DefineNative(native("clock", nil, func(interpreter *Interpreter, arguments []any) any {
+		return float64(interpreter.clock.Now().UnixMilli())
+	}))
+	i.DefineNative(NewNativeFunction("Set", 0, 1, []ArgType{ITERABLE_ARG}, newSet))
+	i.DefineNative(NewNativeFunction("bytes", 0, 2, []ArgType{ANY_ARG, STRING_ARG}, newBytes))
+	i.DefineNative(NewNativeFunction("chan", 0, 1, []ArgType{INTEGER_ARG}, newChannel))
+	i.DefineNative(NewNativeFunction("buffer", 0, 2, []ArgType{ANY_ARG, STRING_ARG}, newBuffer))
+	i.DefineNative(native("Mutex", nil, newMutex))
+	i.DefineNative(native("WaitGroup", nil, newWaitGroup))
+	i.DefineNative(NewNativeFunction("Atomic", 0, 1, []ArgType{ANY_ARG}, newAtomic))
+	i.DefineNative(NewNativeFunction("format", 1, -1, []ArgType{STRING_ARG, ANY_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		return format(arguments[0].(string), arguments[1:])
+	}))
+	i.DefineNative(NewNativeFunction("printf", 1, -1, []ArgType{STRING_ARG, ANY_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		fmt.Print(format(arguments[0].(string), arguments[1:]))
+		return nil
+	}))
+
+	i.DefineGlobal("math", newMathModule())
+	i.DefineGlobal("time", newTimeModule())
+	i.DefineGlobal("json", newJsonModule())
+	i.DefineGlobal("regex", newRegexModule())
+	i.DefineGlobal("csv", newCsvModule())
+	i.DefineGlobal("promise", newPromiseModule())
+	defineFileNatives(i)
+	defineSystemNatives(i)
+	defineRandomNatives(i)
+	defineCollectionFunctions(i)
+	defineExecNatives(i)
+	defineHashNatives(i)
+	defineTimerNatives(i)
 }
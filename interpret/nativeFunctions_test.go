@@ -0,0 +1,52 @@
+package interpret_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kljablon/golox/interpret"
+)
+
+// defineJoin installs a variadic native join(separator, ...parts) taking
+// strings.
+func defineJoin(i *interpret.Interpreter) {
+	i.DefineNative(interpret.NewNativeFunction("join", 1, -1, []interpret.ArgType{interpret.STRING_ARG},
+		func(interpreter *interpret.Interpreter, arguments []any) any {
+			parts := []string{}
+			for _, part := range arguments[1:] {
+				parts = append(parts, part.(string))
+			}
+			return strings.Join(parts, arguments[0].(string))
+		}))
+	i.DefineGlobal("answer", 42.0)
+}
+
+func TestHostNatives(t *testing.T) {
+	expectOutput(t, `
+print join("-", "a", "b", "c");
+print join(",");
+print join;
+print answer;
+`, "a-b-c\n\n<native fn join>\n42\n", defineJoin)
+}
+
+func TestNativeArgumentChecks(t *testing.T) {
+	expectError(t, `
+join();
+`, "Expected at least 1 arguments but got 0.", 2, defineJoin)
+	expectError(t, `
+join("-", "a", 1);
+`, "Argument 3 to join() must be a string.", 2, defineJoin)
+	expectError(t, `
+Set(1, 2);
+`, "Expected 0 to 1 arguments but got 2.", 2, nil)
+}
+
+func TestBoundMethodsAreCallable(t *testing.T) {
+	expectOutput(t, `
+var m = {"a": 1};
+var has = m.has;
+print has("a");
+print clock;
+`, "true\n<native fn clock>\n", nil)
+}
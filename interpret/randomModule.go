@@ -0,0 +1,49 @@
+package interpret
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// SetSeed reseeds the interpreter's random number generator, so that the
+// values scripts get from random() and friends repeat from run to run.
+func (i *Interpreter) SetSeed(seed int64) {
+	i.random.Seed(seed)
+}
+
+func defineRandomNatives(i *Interpreter) {
+	i.random = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	i.DefineNative(native("random", nil, func(interpreter *Interpreter, arguments []any) any {
+		return interpreter.random.Float64()
+	}))
+	i.DefineNative(native("randInt", []ArgType{INTEGER_ARG, INTEGER_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		low, high := arguments[0].(float64), arguments[1].(float64)
+		if low > high {
+			panic(NativeError{"randInt() lower bound is greater than upper bound."})
+		}
+		if high-low >= math.MaxInt64 {
+			panic(NativeError{"randInt() range is too large."})
+		}
+		return low + float64(interpreter.random.Int63n(int64(high-low)+1))
+	}))
+	i.DefineNative(native("choice", []ArgType{LIST_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		elements := arguments[0].(*LoxList).elements
+		if len(elements) == 0 {
+			panic(NativeError{"Can't choose from an empty list."})
+		}
+		return elements[interpreter.random.Intn(len(elements))]
+	}))
+	i.DefineNative(native("shuffle", []ArgType{LIST_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		elements := arguments[0].(*LoxList).elements
+		interpreter.random.Shuffle(len(elements), func(a, b int) {
+			elements[a], elements[b] = elements[b], elements[a]
+		})
+		return nil
+	}))
+	i.DefineNative(native("seed", []ArgType{INTEGER_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		interpreter.SetSeed(int64(arguments[0].(float64)))
+		return nil
+	}))
+}
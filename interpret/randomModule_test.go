@@ -0,0 +1,65 @@
+package interpret_test
+
+import (
+	"testing"
+
+	"github.com/kljablon/golox/interpret"
+)
+
+const randomScript = `
+print random();
+print randInt(1, 6);
+print choice(["a", "b", "c"]);
+var l = [1, 2, 3, 4, 5];
+shuffle(l);
+print l;
+`
+
+func TestSeedRepeatsValues(t *testing.T) {
+	first, err := runScript(t, randomScript, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := runScript(t, randomScript, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Errorf("same seed gave %q and %q", first, second)
+	}
+	other, err := runScript(t, randomScript, func(i *interpret.Interpreter) {
+		i.SetSeed(2)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == first {
+		t.Errorf("seeds 1 and 2 both gave %q", first)
+	}
+}
+
+func TestSeedFromScript(t *testing.T) {
+	expectOutput(t, `
+seed(7);
+var a = (random(), randInt(1, 100));
+seed(7);
+print a == (random(), randInt(1, 100));
+print a == (random(), randInt(1, 100));
+var l = [1, 2, 3];
+shuffle(l);
+print Set(l) == Set([1, 2, 3]);
+for (i in 1..20) {
+  var n = randInt(-2, 2);
+  if (n < -2 or n > 2) print n;
+}
+`, "true\nfalse\ntrue\n", nil)
+}
+
+func TestRandomErrors(t *testing.T) {
+	expectError(t, `
+randInt(3, 1);
+`, "randInt() lower bound is greater than upper bound.", 2, nil)
+	expectError(t, `
+choice([]);
+`, "Can't choose from an empty list.", 2, nil)
+}
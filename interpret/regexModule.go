@@ -0,0 +1,231 @@
+package interpret
+
+import (
+	"errors"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+
+	"github.com/kljablon/golox/ast"
+	"github.com/kljablon/golox/utils"
+)
+
+// LoxRegex is a compiled regular expression, using Go's RE2 syntax.
+type LoxRegex struct {
+	regexp *regexp.Regexp
+}
+
+func compileRegex(pattern string) *LoxRegex {
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		var syntaxErr *syntax.Error
+		if errors.As(err, &syntaxErr) {
+			panic(NativeError{"Invalid regex '" + pattern + "': " + string(syntaxErr.Code) + "."})
+		}
+		panic(NativeError{"Invalid regex '" + pattern + "': " + err.Error() + "."})
+	}
+	return &LoxRegex{compiled}
+}
+
+// toRegex accepts either a compiled regex or a pattern string, so the module
+// functions can be used without compiling first.
+func toRegex(value any, function string) *LoxRegex {
+	switch v := value.(type) {
+	case *LoxRegex:
+		return v
+	case string:
+		return compileRegex(v)
+	}
+	panic(NativeError{"Argument 1 to " + function + "() must be a regex or a string."})
+}
+
+// regexLimit reads the optional limit on the number of matches or pieces, where
+// -1 means no limit.
+func regexLimit(arguments []any, position int) int {
+	if len(arguments) > position {
+		return int(arguments[position].(float64))
+	}
+	return -1
+}
+
+func (r *LoxRegex) match(text string) bool {
+	return r.regexp.MatchString(text)
+}
+
+func (r *LoxRegex) find(text string) any {
+	indexes := r.regexp.FindStringSubmatchIndex(text)
+	if indexes == nil {
+		return nil
+	}
+	return r.newMatch(text, indexes)
+}
+
+func (r *LoxRegex) findAll(text string, n int) *LoxList {
+	matches := []any{}
+	for _, indexes := range r.regexp.FindAllStringSubmatchIndex(text, n) {
+		matches = append(matches, r.newMatch(text, indexes))
+	}
+	return NewLoxList(matches)
+}
+
+// replace substitutes every match in text. A string replacement may refer to
+// groups as $1 or ${name}; a function replacement is called with each match
+// and its result is inserted in its place.
+func (r *LoxRegex) replace(interpreter *Interpreter, text string, replacement any) string {
+	switch v := replacement.(type) {
+	case string:
+		return r.regexp.ReplaceAllString(text, v)
+	case LoxCallable:
+		var out strings.Builder
+		last := 0
+		for _, indexes := range r.regexp.FindAllStringSubmatchIndex(text, -1) {
+			out.WriteString(text[last:indexes[0]])
+			out.WriteString(stringify(callFunction(interpreter, v, []any{r.newMatch(text, indexes)})))
+			last = indexes[1]
+		}
+		out.WriteString(text[last:])
+		return out.String()
+	}
+	panic(NativeError{"Replacement must be a string or a function."})
+}
+
+func (r *LoxRegex) split(text string, n int) *LoxList {
+	pieces := r.regexp.Split(text, n)
+	elements := make([]any, len(pieces))
+	for index, piece := range pieces {
+		elements[index] = piece
+	}
+	return NewLoxList(elements)
+}
+
+func (r *LoxRegex) get(name ast.Token) any {
+	switch name.Lexeme {
+	case "pattern":
+		return r.regexp.String()
+	case "groups":
+		return float64(r.regexp.NumSubexp())
+	case "match":
+		return native("match", []ArgType{STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			return r.match(arguments[0].(string))
+		})
+	case "find":
+		return native("find", []ArgType{STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			return r.find(arguments[0].(string))
+		})
+	case "findAll":
+		return NewNativeFunction("findAll", 1, 2, []ArgType{STRING_ARG, INTEGER_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			return r.findAll(arguments[0].(string), regexLimit(arguments, 1))
+		})
+	case "replace":
+		return native("replace", []ArgType{STRING_ARG, ANY_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			return r.replace(interpreter, arguments[0].(string), arguments[1])
+		})
+	case "split":
+		return NewNativeFunction("split", 1, 2, []ArgType{STRING_ARG, INTEGER_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			return r.split(arguments[0].(string), regexLimit(arguments, 1))
+		})
+	}
+	panic(utils.NewRuntimeError(name, "Undefined property '"+name.Lexeme+"'."))
+}
+
+func (r *LoxRegex) toString() string {
+	return "<regex " + r.regexp.String() + ">"
+}
+
+// LoxMatch is one match of a regex. Its start and end are code-point offsets
+// into the searched string, like the results of indexOf().
+type LoxMatch struct {
+	text   string
+	start  float64
+	end    float64
+	groups []any
+	names  []string
+}
+
+func (r *LoxRegex) newMatch(text string, indexes []int) *LoxMatch {
+	groups := make([]any, len(indexes)/2)
+	for group := range groups {
+		if start := indexes[2*group]; start >= 0 {
+			groups[group] = text[start:indexes[2*group+1]]
+		}
+	}
+	return &LoxMatch{
+		text:   groups[0].(string),
+		start:  runeOffset(text, indexes[0]),
+		end:    runeOffset(text, indexes[1]),
+		groups: groups,
+		names:  r.regexp.SubexpNames(),
+	}
+}
+
+func (m *LoxMatch) get(name ast.Token) any {
+	switch name.Lexeme {
+	case "text":
+		return m.text
+	case "start":
+		return m.start
+	case "end":
+		return m.end
+	case "groups":
+		// Groups that took no part in the match are nil.
+		return NewLoxList(append([]any{}, m.groups[1:]...))
+	case "named":
+		named := NewLoxMap()
+		for group, groupName := range m.names {
+			if groupName != "" {
+				named.setKey(name, groupName, m.groups[group])
+			}
+		}
+		return named
+	case "group":
+		return native("group", []ArgType{ANY_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			switch v := arguments[0].(type) {
+			case float64:
+				if v != float64(int(v)) || int(v) < 0 || int(v) >= len(m.groups) {
+					panic(NativeError{"No group " + stringify(v) + " in match."})
+				}
+				return m.groups[int(v)]
+			case string:
+				for group, groupName := range m.names {
+					if groupName == v && v != "" {
+						return m.groups[group]
+					}
+				}
+				panic(NativeError{"No group named '" + v + "' in match."})
+			}
+			panic(NativeError{"Argument 1 to group() must be a number or a string."})
+		})
+	}
+	panic(utils.NewRuntimeError(name, "Undefined property '"+name.Lexeme+"'."))
+}
+
+func (m *LoxMatch) toString() string {
+	return "<match " + repr(m.text) + ">"
+}
+
+func newRegexModule() *LoxModule {
+	module := NewLoxModule("regex")
+
+	module.DefineNative(native("compile", []ArgType{STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		return compileRegex(arguments[0].(string))
+	}))
+	module.DefineNative(native("escape", []ArgType{STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		return regexp.QuoteMeta(arguments[0].(string))
+	}))
+	module.DefineNative(native("match", []ArgType{ANY_ARG, STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		return toRegex(arguments[0], "match").match(arguments[1].(string))
+	}))
+	module.DefineNative(native("find", []ArgType{ANY_ARG, STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		return toRegex(arguments[0], "find").find(arguments[1].(string))
+	}))
+	module.DefineNative(NewNativeFunction("findAll", 2, 3, []ArgType{ANY_ARG, STRING_ARG, INTEGER_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		return toRegex(arguments[0], "findAll").findAll(arguments[1].(string), regexLimit(arguments, 2))
+	}))
+	module.DefineNative(native("replace", []ArgType{ANY_ARG, STRING_ARG, ANY_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		return toRegex(arguments[0], "replace").replace(interpreter, arguments[1].(string), arguments[2])
+	}))
+	module.DefineNative(NewNativeFunction("split", 2, 3, []ArgType{ANY_ARG, STRING_ARG, INTEGER_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		return toRegex(arguments[0], "split").split(arguments[1].(string), regexLimit(arguments, 2))
+	}))
+	return module
+}
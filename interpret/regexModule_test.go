@@ -0,0 +1,49 @@
+package interpret_test
+
+import "testing"
+
+func TestRegexMatches(t *testing.T) {
+	expectOutput(t, `
+var r = regex.compile("(?P<word>[a-zé]+)(\d)");
+print r;
+print r.groups;
+print r.match("héllo1");
+print r.match("!!");
+var m = r.find("xx héllo1 abc2");
+print m;
+print m.start;
+print m.end;
+print m.groups;
+print m.named;
+print m.group(1);
+print m.group("word");
+print r.find("none");
+print [m.text for m in r.findAll("a1 b2 c3")];
+print r.findAll("a1 b2 c3", 2).length;
+`, "<regex (?P<word>[a-zé]+)(\\d)>\n2\ntrue\nfalse\n<match \"héllo1\">\n3\n9\n[\"héllo\", \"1\"]\n{\"word\": \"héllo\"}\nhéllo\nhéllo\nnil\n[\"a1\", \"b2\", \"c3\"]\n2\n", nil)
+}
+
+func TestRegexReplaceAndSplit(t *testing.T) {
+	expectOutput(t, `
+var r = regex.compile("(?P<word>[a-z]+)(\d)");
+print r.replace("a1 b2", "<$2$word>");
+fun shout(m) { return m.group("word").upper(); }
+print r.replace("a1 b2", shout);
+print regex.split(",\s*", "a, b,c");
+print regex.split(",", "a,b,c", 2);
+print regex.match("^\d+$", "123");
+print regex.escape("a.b*c");
+`, "<1a> <2b>\nA B\n[\"a\", \"b\", \"c\"]\n[\"a\", \"b,c\"]\ntrue\na\\.b\\*c\n", nil)
+}
+
+func TestRegexErrors(t *testing.T) {
+	expectError(t, `
+regex.compile("(");
+`, "Invalid regex '(': missing closing ).", 2, nil)
+	expectError(t, `
+regex.match(1, "a");
+`, "Argument 1 to match() must be a regex or a string.", 2, nil)
+	expectError(t, `
+regex.find("a", "a").group(3);
+`, "No group 3 in match.", 2, nil)
+}
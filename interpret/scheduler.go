@@ -0,0 +1,279 @@
+package interpret
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kljablon/golox/ast"
+	"github.com/kljablon/golox/utils"
+)
+
+// yieldInterval is how many statements a task executes before it gives
+// other tasks a turn.
+const yieldInterval = 1000
+
+const deadlockMessage = "Deadlock: every task is blocked."
+
+// scheduler coordinates the tasks started with spawn. Every task runs on its
+// own goroutine, but only the one holding lock executes Lox code; the others
+// wait for it to block on a channel or timer, or to yield. Environments and
+// values are shared between tasks, and the lock is what keeps them
+// consistent.
+//
+// Channels, mutexes, wait groups and task handles park a task that has to
+// wait for another one, and the task that lets it continue wakes it, both
+// while holding the lock. That keeps an exact count of the tasks that can
+// only be woken by others, so a deadlock is noticed as soon as every live
+// task is among them.
+type scheduler struct {
+	lock sync.Mutex
+
+	// live counts the main script and the spawned tasks that haven't
+	// finished, and parked the waiters of those waiting on another task.
+	// Both are guarded by lock.
+	live   int
+	parked []*waiter
+
+	// stopped is set, and done closed, when the script ends or a task
+	// fails. failure holds the error a task failed with, if any.
+	stopped atomic.Bool
+	done    chan struct{}
+	failure any
+	once    sync.Once
+}
+
+func newScheduler() *scheduler {
+	return &scheduler{live: 1, done: make(chan struct{})}
+}
+
+// restart returns a scheduler for the next script, locked. Values such as
+// channels keep the scheduler they were made with, so it is reused unless
+// tasks of the last script are still unwinding.
+func (s *scheduler) restart() *scheduler {
+	s.lock.Lock()
+	if s.live > 1 {
+		s.lock.Unlock()
+		s = newScheduler()
+		s.lock.Lock()
+		return s
+	}
+	s.parked = nil
+	s.stopped.Store(false)
+	s.done = make(chan struct{})
+	s.failure = nil
+	s.once = sync.Once{}
+	return s
+}
+
+// taskStopped unwinds a task that was still running when the script ended.
+type taskStopped struct{}
+
+// fail stops every task. A non-nil failure, a utils.RuntimeError or an
+// ExitError, is raised in the main script so Interpret returns it.
+func (s *scheduler) fail(failure any) {
+	s.once.Do(func() {
+		s.failure = failure
+		s.stopped.Store(true)
+		close(s.done)
+	})
+}
+
+func (s *scheduler) stop() {
+	s.fail(nil)
+}
+
+// checkStopped unwinds the current task if the scheduler has stopped.
+func (s *scheduler) checkStopped() {
+	if !s.stopped.Load() {
+		return
+	}
+	if s.failure != nil {
+		panic(s.failure)
+	}
+	panic(taskStopped{})
+}
+
+// tick is called before every statement. It lets other tasks run now and
+// then, so a task that never blocks can't starve the rest.
+func (s *scheduler) tick(interpreter *Interpreter) {
+	s.checkStopped()
+	interpreter.steps++
+	if interpreter.steps%yieldInterval == 0 && s.live > 1 {
+		s.lock.Unlock()
+		runtime.Gosched()
+		s.lock.Lock()
+		s.checkStopped()
+	}
+}
+
+// block runs wait without holding the lock, so other tasks can run while
+// this one sleeps or waits for a process. wait must finish on its own.
+func (s *scheduler) block(wait func()) {
+	s.lock.Unlock()
+	defer func() {
+		s.lock.Lock()
+		s.checkStopped()
+	}()
+	wait()
+}
+
+// waiter is a parked task. Whoever wakes it sets fired and leaves it a
+// value; index tells a select which of its cases was taken. ok is false when
+// a channel was closed on it.
+type waiter struct {
+	woken    chan struct{}
+	fired    bool
+	deadlock bool
+	index    int
+	value    any
+	ok       bool
+}
+
+func newWaiter() *waiter {
+	return &waiter{woken: make(chan struct{})}
+}
+
+// park releases the lock until w is woken. A nil timeout means w can only be
+// woken by another task; if every other live task is then parked as well,
+// the script is deadlocked. park reports whether the timeout fired first.
+func (s *scheduler) park(w *waiter, timeout <-chan time.Time) bool {
+	if timeout == nil {
+		if len(s.parked)+1 == s.live {
+			panic(NativeError{deadlockMessage})
+		}
+		s.parked = append(s.parked, w)
+	}
+
+	s.lock.Unlock()
+	select {
+	case <-w.woken:
+	case <-timeout:
+	case <-s.done:
+	}
+	s.lock.Lock()
+
+	timedOut := !w.fired
+	if timedOut {
+		w.fired = true
+		s.unpark(w)
+	}
+	s.checkStopped()
+	if w.deadlock {
+		panic(NativeError{deadlockMessage})
+	}
+	return timedOut
+}
+
+func (s *scheduler) unpark(w *waiter) {
+	for index, parked := range s.parked {
+		if parked == w {
+			s.parked = append(s.parked[:index], s.parked[index+1:]...)
+			return
+		}
+	}
+}
+
+// wake lets a parked task continue, unless something else already has. It
+// reports whether this call was the one that woke it.
+func (s *scheduler) wake(w *waiter, index int, value any, ok bool) bool {
+	if w.fired {
+		return false
+	}
+	w.fired = true
+	w.index = index
+	w.value = value
+	w.ok = ok
+	s.unpark(w)
+	close(w.woken)
+	return true
+}
+
+// exit removes a finished task. If the tasks left are all parked, none of
+// them can ever be woken, so the one that has waited longest is woken with a
+// deadlock error.
+func (s *scheduler) exit() {
+	s.live--
+	if s.live > 0 && len(s.parked) == s.live {
+		w := s.parked[0]
+		w.deadlock = true
+		s.wake(w, 0, nil, false)
+	}
+}
+
+// spawn calls function with arguments on a new task. Errors in the task stop
+// the whole script; paren locates errors raised by a native function.
+func (s *scheduler) spawn(parent *Interpreter, paren ast.Token, function LoxCallable, arguments []any) *LoxTask {
+	task := &LoxTask{scheduler: s}
+	interpreter := *parent
+	interpreter.steps = 0
+	interpreter.loop = newEventLoop()
+	interpreter.coroutine = nil
+	interpreter.callSite = paren
+
+	s.live++
+	go func() {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		defer func() {
+			task.finished = true
+			for _, w := range task.waiters {
+				s.wake(w, 0, nil, true)
+			}
+			s.exit()
+		}()
+		defer func() {
+			if r := recover(); r != nil {
+				switch r := r.(type) {
+				case taskStopped:
+				case NativeError:
+					s.fail(utils.NewRuntimeError(paren, r.Message))
+				case utils.RuntimeError, ExitError:
+					s.fail(r)
+				default:
+					panic(r)
+				}
+			}
+		}()
+		s.checkStopped()
+		task.result = function.call(&interpreter, arguments)
+		interpreter.loop.run(&interpreter, nil)
+		if promise, ok := task.result.(*LoxPromise); ok && promise.settled {
+			task.result = promise.value
+		}
+	}()
+	return task
+}
+
+// LoxTask is the handle spawn returns for a running function.
+type LoxTask struct {
+	scheduler *scheduler
+	finished  bool
+	result    any
+	waiters   []*waiter
+}
+
+func (t *LoxTask) get(name ast.Token) any {
+	switch name.Lexeme {
+	case "finished":
+		return t.finished
+	case "wait":
+		return native("wait", nil, func(interpreter *Interpreter, arguments []any) any {
+			if !t.finished {
+				w := newWaiter()
+				t.waiters = append(t.waiters, w)
+				t.scheduler.park(w, nil)
+			}
+			return t.result
+		})
+	}
+	panic(utils.NewRuntimeError(name, "Undefined property '"+name.Lexeme+"'."))
+}
+
+func (t *LoxTask) toString() string {
+	if t.finished {
+		return "<task finished>"
+	}
+	return "<task>"
+}
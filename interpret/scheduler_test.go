@@ -0,0 +1,89 @@
+package interpret_test
+
+import "testing"
+
+const deadlock = "Deadlock: every task is blocked."
+
+func TestDeadlockOnChannel(t *testing.T) {
+	expectError(t, `
+var c = chan();
+c.receive();
+`, deadlock, 3, nil)
+}
+
+func TestDeadlockOnMutex(t *testing.T) {
+	expectError(t, `
+var m = Mutex();
+m.lock();
+m.lock();
+`, deadlock, 4, nil)
+}
+
+func TestDeadlockOnWaitGroup(t *testing.T) {
+	expectError(t, `
+var wg = WaitGroup();
+wg.add(1);
+wg.wait();
+`, deadlock, 4, nil)
+}
+
+// The last task that could call done finishes without it, leaving the main
+// script waiting forever.
+func TestDeadlockWhenLastTaskExits(t *testing.T) {
+	expectError(t, `
+var wg = WaitGroup();
+wg.add(2);
+fun work() { wg.done(); }
+spawn work();
+wg.wait();
+`, deadlock, 6, nil)
+}
+
+func TestDeadlockOnTaskWait(t *testing.T) {
+	expectError(t, `
+var c = chan();
+fun work() { c.receive(); }
+var task = spawn work();
+task.wait();
+`, deadlock, 3, nil)
+}
+
+func TestNoDeadlockWhileTasksCanRun(t *testing.T) {
+	expectOutput(t, `
+var m = Mutex();
+var wg = WaitGroup();
+var total = Atomic(0);
+fun increment() { total.add(1); }
+fun work() { m.withLock(increment); wg.done(); }
+wg.add(3);
+for (i in 0..<3) spawn work();
+wg.wait();
+print total.get();
+print m.locked;
+`, "3\nfalse\n", nil)
+}
+
+func TestAtomic(t *testing.T) {
+	expectOutput(t, `
+var a = Atomic(5);
+print a.add();
+print a.add(3);
+print a.swap(1);
+print a.compareAndSwap(1, 2);
+print a.compareAndSwap(1, 3);
+print a.get();
+a.set("x");
+print a;
+`, "6\n9\n9\ntrue\nfalse\n2\nAtomic(\"x\")\n", nil)
+}
+
+func TestSyncErrors(t *testing.T) {
+	expectError(t, `
+var wg = WaitGroup();
+wg.done();
+`, "WaitGroup counter can't go below zero.", 3, nil)
+	expectError(t, `
+var m = Mutex();
+m.unlock();
+`, "Mutex is not locked.", 3, nil)
+}
@@ -0,0 +1,90 @@
+package interpret_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/kljablon/golox/interpret"
+	"github.com/kljablon/golox/parse"
+	"github.com/kljablon/golox/resolve"
+	"github.com/kljablon/golox/utils"
+)
+
+// epoch is where the fake clock of every test script starts.
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// runScript runs source on a fresh interpreter with a fake clock and a fixed
+// seed, returning what it printed and the error it stopped with. setup, if
+// not nil, can configure the interpreter further first.
+func runScript(t *testing.T, source string, setup func(i *interpret.Interpreter)) (string, error) {
+	t.Helper()
+	interpreter := interpret.NewInterpreter()
+	interpreter.SetClock(interpret.NewFakeClock(epoch))
+	interpreter.SetSeed(1)
+	if setup != nil {
+		setup(&interpreter)
+	}
+	scanner := parse.NewScanner(source)
+	parser := parse.NewParser(scanner.ScanTokens())
+	statements := parser.Parse()
+	resolver := resolve.NewResover(&interpreter)
+	resolver.ResolveStmts(statements)
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	output := make(chan string)
+	go func() {
+		var buffer bytes.Buffer
+		io.Copy(&buffer, reader)
+		output <- buffer.String()
+	}()
+	defer func() {
+		os.Stdout = stdout
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- interpreter.Interpret(statements)
+	}()
+	select {
+	case err = <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("script didn't finish")
+	}
+	writer.Close()
+	printed := <-output
+	reader.Close()
+	return printed, err
+}
+
+// expectOutput runs source and checks that it printed want without error.
+func expectOutput(t *testing.T, source string, want string, setup func(i *interpret.Interpreter)) {
+	t.Helper()
+	got, err := runScript(t, source, setup)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+// expectError runs source and checks that it failed with message on line.
+func expectError(t *testing.T, source string, message string, line int, setup func(i *interpret.Interpreter)) {
+	t.Helper()
+	_, err := runScript(t, source, setup)
+	runtimeErr, ok := err.(utils.RuntimeError)
+	if !ok {
+		t.Fatalf("error = %v, want a runtime error", err)
+	}
+	if runtimeErr.Message != message || runtimeErr.Token.Line != line {
+		t.Errorf("error = %q on line %d, want %q on line %d", runtimeErr.Message, runtimeErr.Token.Line, message, line)
+	}
+}
@@ -0,0 +1,131 @@
+package interpret
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/kljablon/golox/ast"
+	"github.com/kljablon/golox/utils"
+)
+
+// VisitStmt_Select evaluates the channels and values of every case, then
+// runs the body of a case whose channel is ready, picking one at random if
+// several are. If none is, it runs the default case if there is one, and
+// otherwise waits for a channel or the first timeout, letting other tasks
+// run meanwhile. A recv case on a closed channel is always ready and
+// receives nil.
+func (i *Interpreter) VisitStmt_Select(stmt ast.Stmt_Select) {
+	channels := make([]*LoxChannel, len(stmt.Cases))
+	values := make([]any, len(stmt.Cases))
+	timeoutCase := -1
+	var timeout time.Duration
+
+	for index, selectCase := range stmt.Cases {
+		operand := i.evaluate(selectCase.Operand)
+		if selectCase.Kind.Lexeme == "timeout" {
+			milliseconds, ok := operand.(float64)
+			if !ok {
+				panic(utils.NewRuntimeError(selectCase.Kind, "Timeout must be a number of milliseconds."))
+			}
+			if duration := toDuration(milliseconds); timeoutCase < 0 || duration < timeout {
+				timeoutCase, timeout = index, duration
+			}
+			continue
+		}
+		channel, ok := operand.(*LoxChannel)
+		if !ok {
+			panic(utils.NewRuntimeError(selectCase.Kind, "Argument to "+selectCase.Kind.Lexeme+" must be a channel."))
+		}
+		channels[index] = channel
+		if selectCase.Kind.Lexeme == "send" {
+			values[index] = i.evaluate(selectCase.Value)
+		}
+	}
+
+	// Take a ready channel if there is one, without waiting. The order comes
+	// from the unseeded global source, so a select doesn't shift the values
+	// a seeded script gets from random().
+	for _, index := range rand.Perm(len(stmt.Cases)) {
+		channel := channels[index]
+		if channel == nil {
+			continue
+		}
+		selectCase := stmt.Cases[index]
+		if selectCase.Kind.Lexeme == "send" {
+			var sent bool
+			withNativeErrorAt(selectCase.Kind, func() { sent = channel.trySend(values[index]) })
+			if sent {
+				i.runSelectCase(selectCase, nil)
+				return
+			}
+		} else if value, _, ready := channel.tryReceive(); ready {
+			i.runSelectCase(selectCase, value)
+			return
+		}
+	}
+	if stmt.Default != nil {
+		i.execute(stmt.Default)
+		return
+	}
+
+	w := newWaiter()
+	for index, channel := range channels {
+		if channel == nil {
+			continue
+		}
+		waiting := channelWaiter{w, index, values[index]}
+		if stmt.Cases[index].Kind.Lexeme == "send" {
+			channel.senders = append(channel.senders, waiting)
+		} else {
+			channel.receivers = append(channel.receivers, waiting)
+		}
+	}
+	var after <-chan time.Time
+	if timeoutCase >= 0 {
+		after = i.clock.After(timeout)
+	}
+	var timedOut bool
+	withNativeErrorAt(stmt.Keyword, func() {
+		defer func() {
+			for _, channel := range channels {
+				if channel != nil {
+					channel.removeWaiter(w)
+				}
+			}
+		}()
+		timedOut = i.scheduler.park(w, after)
+	})
+
+	if timedOut {
+		i.runSelectCase(stmt.Cases[timeoutCase], nil)
+		return
+	}
+	selectCase := stmt.Cases[w.index]
+	if selectCase.Kind.Lexeme == "send" && !w.ok {
+		panic(utils.NewRuntimeError(selectCase.Kind, "Can't send on a closed channel."))
+	}
+	i.runSelectCase(selectCase, w.value)
+}
+
+func (i *Interpreter) runSelectCase(selectCase ast.SelectCase, received any) {
+	enclosing_env := i.environment
+	case_env := NewEnvironmentWithEnclosing(&enclosing_env)
+	if selectCase.Name != nil {
+		case_env.define(selectCase.Name.Lexeme, received)
+	}
+	i.executeBlock([]ast.Stmt{selectCase.Body}, &case_env)
+}
+
+// withNativeErrorAt runs action, reporting a NativeError it raises as a
+// runtime error at token, the way calls report the errors of natives.
+func withNativeErrorAt(token ast.Token, action func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			if err, ok := r.(NativeError); ok {
+				panic(utils.NewRuntimeError(token, err.Message))
+			}
+			panic(r)
+		}
+	}()
+	action()
+}
@@ -0,0 +1,112 @@
+package interpret_test
+
+import "testing"
+
+func TestSelectTimesOut(t *testing.T) {
+	expectOutput(t, `
+var c = chan();
+var start = clock();
+select {
+  case v = recv(c) => print "got " + v;
+  case timeout(50) => print "timeout";
+}
+print clock() - start;
+`, "timeout\n50\n", nil)
+}
+
+func TestSelectPrefersReadyChannel(t *testing.T) {
+	expectOutput(t, `
+var c = chan(1);
+c.send("ready");
+select {
+  case timeout(0) => print "timeout";
+  case v = recv(c) => print "got " + v;
+}
+`, "got ready\n", nil)
+}
+
+func TestSelectReceivesFromWaitingSender(t *testing.T) {
+	expectOutput(t, `
+var c = chan();
+var result = chan(1);
+fun receiver() {
+  select {
+    case v = recv(c) => result.send("got " + v);
+    case timeout(1000) => result.send("timeout");
+  }
+}
+spawn receiver();
+c.send("sent");
+print result.receive();
+`, "got sent\n", nil)
+}
+
+func TestSelectSendTimesOutOnFullChannel(t *testing.T) {
+	expectOutput(t, `
+var c = chan(1);
+select {
+  case send(c, 1) => print "sent";
+  case timeout(50) => print "timeout";
+}
+select {
+  case send(c, 2) => print "sent";
+  case timeout(50) => print "timeout";
+}
+print c.receive();
+`, "sent\ntimeout\n1\n", nil)
+}
+
+func TestSelectUsesShortestTimeout(t *testing.T) {
+	expectOutput(t, `
+var c = chan();
+var start = clock();
+select {
+  case timeout(80) => print "80";
+  case timeout(30) => print "30";
+  case recv(c) => print "got";
+}
+print clock() - start;
+`, "30\n30\n", nil)
+}
+
+func TestSelectDefault(t *testing.T) {
+	expectOutput(t, `
+var c = chan();
+select {
+  case recv(c) => print "got";
+  default => print "default";
+}
+`, "default\n", nil)
+}
+
+func TestSelectErrors(t *testing.T) {
+	expectError(t, `
+select {
+  case recv(1) => print "got";
+}
+`, "Argument to recv must be a channel.", 3, nil)
+	expectError(t, `
+var c = chan();
+select {
+  case recv(c) => print "got";
+}
+`, deadlock, 3, nil)
+}
+
+func TestSelectKeepsRandomSequence(t *testing.T) {
+	expectOutput(t, `
+var a = chan(1);
+var b = chan(1);
+seed(7);
+var first = (random(), random());
+seed(7);
+print random() == first[0];
+a.send(1);
+b.send(2);
+select {
+  case recv(a) => nil;
+  case recv(b) => nil;
+}
+print random() == first[1];
+`, "true\ntrue\n", nil)
+}
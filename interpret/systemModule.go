@@ -0,0 +1,121 @@
+package interpret
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ExitError is returned by Interpret when a script calls exit(). The
+// interpreter unwinds normally before returning it, so the host decides what
+// to do with the exit code.
+type ExitError struct {
+	Code int
+}
+
+func (e ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// SetArgs sets the list of strings scripts see as the global args.
+func (i *Interpreter) SetArgs(args []string) {
+	elements := make([]any, len(args))
+	for index, arg := range args {
+		elements[index] = arg
+	}
+	i.DefineGlobal("args", NewLoxList(elements))
+}
+
+// stdin buffers standard input for every interpreter in the process, so that
+// none of them reads ahead past lines meant for another.
+var stdin = bufio.NewReader(os.Stdin)
+
+// SetInput replaces the stream input(), readLine() and readAll() read from,
+// which is standard input by default. A *bufio.Reader is used as is, so a
+// host can share one it also reads from.
+func (i *Interpreter) SetInput(input io.Reader) {
+	if reader, ok := input.(*bufio.Reader); ok {
+		i.input = reader
+		return
+	}
+	i.input = bufio.NewReader(input)
+}
+
+// inputLock serializes reads of the input. Tasks read it without holding the
+// interpreter lock, so that one waiting for a line doesn't stop the others.
+var inputLock sync.Mutex
+
+// readLine reads the next line of input without its line ending, reporting
+// false at the end of the input.
+func readLine(interpreter *Interpreter) (string, bool) {
+	var line string
+	var err error
+	interpreter.scheduler.block(func() {
+		inputLock.Lock()
+		defer inputLock.Unlock()
+		line, err = interpreter.input.ReadString('\n')
+	})
+	if err != nil && err != io.EOF {
+		panic(NativeError{"Can't read input: " + err.Error() + "."})
+	}
+	if err == io.EOF && line == "" {
+		return "", false
+	}
+	line = strings.TrimSuffix(line, "\n")
+	return strings.TrimSuffix(line, "\r"), true
+}
+
+func defineSystemNatives(i *Interpreter) {
+	i.SetArgs(nil)
+	i.SetInput(stdin)
+	i.DefineNative(native("env", []ArgType{STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		if value, ok := os.LookupEnv(arguments[0].(string)); ok {
+			return value
+		}
+		return nil
+	}))
+	i.DefineNative(native("setEnv", []ArgType{STRING_ARG, STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		if err := os.Setenv(arguments[0].(string), arguments[1].(string)); err != nil {
+			panic(NativeError{"Can't set environment variable '" + arguments[0].(string) + "': " + err.Error() + "."})
+		}
+		return nil
+	}))
+	i.DefineNative(NewNativeFunction("exit", 0, 1, []ArgType{INTEGER_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		code := 0
+		if len(arguments) == 1 {
+			code = int(arguments[0].(float64))
+		}
+		panic(ExitError{code})
+	}))
+	i.DefineNative(NewNativeFunction("input", 0, 1, []ArgType{ANY_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		if len(arguments) == 1 {
+			fmt.Print(stringify(arguments[0]))
+		}
+		if line, ok := readLine(interpreter); ok {
+			return line
+		}
+		return nil
+	}))
+	i.DefineNative(native("readLine", nil, func(interpreter *Interpreter, arguments []any) any {
+		if line, ok := readLine(interpreter); ok {
+			return line
+		}
+		return nil
+	}))
+	i.DefineNative(native("readAll", nil, func(interpreter *Interpreter, arguments []any) any {
+		var data []byte
+		var err error
+		interpreter.scheduler.block(func() {
+			inputLock.Lock()
+			defer inputLock.Unlock()
+			data, err = io.ReadAll(interpreter.input)
+		})
+		if err != nil {
+			panic(NativeError{"Can't read input: " + err.Error() + "."})
+		}
+		return string(data)
+	}))
+}
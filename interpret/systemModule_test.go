@@ -0,0 +1,125 @@
+package interpret_test
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kljablon/golox/interpret"
+)
+
+func TestArgs(t *testing.T) {
+	expectOutput(t, `
+print args;
+print args.length;
+`, "[\"one\", \"two words\"]\n2\n", func(i *interpret.Interpreter) {
+		i.SetArgs([]string{"one", "two words"})
+	})
+	expectOutput(t, `
+print args;
+`, "[]\n", nil)
+}
+
+func TestEnv(t *testing.T) {
+	t.Setenv("GOLOX_TEST_ENV", "set")
+	expectOutput(t, `
+print env("GOLOX_TEST_ENV");
+setEnv("GOLOX_TEST_ENV", "changed");
+print env("GOLOX_TEST_ENV");
+print env("GOLOX_TEST_UNSET");
+`, "set\nchanged\nnil\n", nil)
+}
+
+func TestExit(t *testing.T) {
+	output, err := runScript(t, `
+fun stop() {
+  print "before";
+  exit(3);
+  print "after";
+}
+stop();
+print "not reached";
+`, nil)
+	var exit interpret.ExitError
+	if !errors.As(err, &exit) || exit.Code != 3 {
+		t.Fatalf("error = %v, want exit status 3", err)
+	}
+	if output != "before\n" {
+		t.Errorf("output = %q, want %q", output, "before\n")
+	}
+	_, err = runScript(t, `
+exit();
+`, nil)
+	if !errors.As(err, &exit) || exit.Code != 0 {
+		t.Errorf("error = %v, want exit status 0", err)
+	}
+}
+
+func TestReadInput(t *testing.T) {
+	expectOutput(t, `
+print input("name? ");
+print readLine();
+print readAll();
+print readLine();
+print input();
+`, "name? ada\nline two\nrest\nof it\nnil\nnil\n", func(i *interpret.Interpreter) {
+		i.SetInput(strings.NewReader("ada\nline two\r\nrest\nof it"))
+	})
+}
+
+// Interpreters given the same *bufio.Reader take turns on it, so one never
+// buffers lines that the next should read.
+func TestSharedInput(t *testing.T) {
+	input := bufio.NewReader(strings.NewReader("first\nsecond\n"))
+	share := func(i *interpret.Interpreter) {
+		i.SetInput(input)
+	}
+	expectOutput(t, `
+print readLine();
+`, "first\n", share)
+	expectOutput(t, `
+print readLine();
+`, "second\n", share)
+}
+
+// gatedReader holds back its input until the file gate exists, so a test
+// can tell whether the script kept running while a read was waiting.
+type gatedReader struct {
+	gate  string
+	input *strings.Reader
+}
+
+func (g gatedReader) Read(p []byte) (int, error) {
+	for {
+		if _, err := os.Stat(g.gate); err == nil {
+			return g.input.Read(p)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestTasksRunWhileReadingInput(t *testing.T) {
+	dir := t.TempDir()
+	expectOutput(t, `
+var started = chan(1);
+var lines = chan(1);
+fun reader() {
+  started.send(true);
+  lines.send(readLine());
+}
+spawn reader();
+started.receive();
+for (i in 0..<3) print i;
+writeFile("gate", "open");
+print lines.receive();
+`, "0\n1\n2\nhello\n", func(i *interpret.Interpreter) {
+		if err := i.SetFileRoot(dir); err != nil {
+			t.Fatal(err)
+		}
+		i.SetInput(gatedReader{filepath.Join(dir, "gate"), strings.NewReader("hello\n")})
+	})
+}
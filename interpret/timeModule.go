@@ -0,0 +1,166 @@
+package interpret
+
+import (
+	"time"
+
+	"github.com/kljablon/golox/ast"
+	"github.com/kljablon/golox/utils"
+)
+
+// Durations are plain numbers of milliseconds throughout the time module, so
+// they can be added, compared and printed like any other number.
+
+func toDuration(milliseconds float64) time.Duration {
+	return time.Duration(milliseconds * float64(time.Millisecond))
+}
+
+func fromDuration(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func loadLocation(name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		panic(NativeError{"Unknown time zone '" + name + "'."})
+	}
+	return location
+}
+
+// LoxTime is an instant in time together with the time zone it is shown in.
+type LoxTime struct {
+	time time.Time
+}
+
+func (t *LoxTime) get(name ast.Token) any {
+	switch name.Lexeme {
+	case "year":
+		return float64(t.time.Year())
+	case "month":
+		return float64(t.time.Month())
+	case "day":
+		return float64(t.time.Day())
+	case "hour":
+		return float64(t.time.Hour())
+	case "minute":
+		return float64(t.time.Minute())
+	case "second":
+		return float64(t.time.Second())
+	case "millisecond":
+		return float64(t.time.Nanosecond() / int(time.Millisecond))
+	case "weekday":
+		return t.time.Weekday().String()
+	case "zone":
+		return t.time.Location().String()
+	case "unix":
+		return float64(t.time.UnixMilli()) / 1000
+	case "unixMillis":
+		return float64(t.time.UnixMilli())
+	case "format":
+		return NewNativeFunction("format", 0, 1, []ArgType{STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			layout := time.RFC3339
+			if len(arguments) == 1 {
+				layout = arguments[0].(string)
+			}
+			return t.time.Format(layout)
+		})
+	case "inZone":
+		return native("inZone", []ArgType{STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			return &LoxTime{t.time.In(loadLocation(arguments[0].(string)))}
+		})
+	case "add":
+		return native("add", []ArgType{NUMBER_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			return &LoxTime{t.time.Add(toDuration(arguments[0].(float64)))}
+		})
+	case "since":
+		return native("since", []ArgType{ANY_ARG}, func(interpreter *Interpreter, arguments []any) any {
+			other, ok := arguments[0].(*LoxTime)
+			if !ok {
+				panic(NativeError{"Argument 1 to since() must be a time."})
+			}
+			return fromDuration(t.time.Sub(other.time))
+		})
+	}
+	panic(utils.NewRuntimeError(name, "Undefined property '"+name.Lexeme+"'."))
+}
+
+func (t *LoxTime) Equals(other any) bool {
+	o, ok := other.(*LoxTime)
+	return ok && t.time.Equal(o.time)
+}
+
+func (t *LoxTime) toString() string {
+	return t.time.Format(time.RFC3339Nano)
+}
+
+func newTimeModule() *LoxModule {
+	module := NewLoxModule("time")
+
+	module.DefineNative(NewNativeFunction("now", 0, 1, []ArgType{STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		now := interpreter.clock.Now()
+		if len(arguments) == 1 {
+			now = now.In(loadLocation(arguments[0].(string)))
+		}
+		return &LoxTime{now}
+	}))
+	module.DefineNative(NewNativeFunction("fromUnix", 1, 2, []ArgType{NUMBER_ARG, STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		instant := time.UnixMilli(int64(arguments[0].(float64) * 1000))
+		if len(arguments) == 2 {
+			instant = instant.In(loadLocation(arguments[1].(string)))
+		}
+		return &LoxTime{instant}
+	}))
+	module.DefineNative(NewNativeFunction("date", 3, 7, []ArgType{INTEGER_ARG, INTEGER_ARG, INTEGER_ARG, INTEGER_ARG, INTEGER_ARG, NUMBER_ARG, STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		parts := [5]int{}
+		for index := 0; index < 5 && index < len(arguments); index++ {
+			parts[index] = int(arguments[index].(float64))
+		}
+		seconds := 0.0
+		if len(arguments) > 5 {
+			seconds = arguments[5].(float64)
+		}
+		location := time.UTC
+		if len(arguments) > 6 {
+			location = loadLocation(arguments[6].(string))
+		}
+		instant := time.Date(parts[0], time.Month(parts[1]), parts[2], parts[3], parts[4], 0, 0, location)
+		return &LoxTime{instant.Add(toDuration(seconds * 1000))}
+	}))
+	module.DefineNative(NewNativeFunction("parse", 1, 3, []ArgType{STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		layout := time.RFC3339
+		if len(arguments) > 1 {
+			layout = arguments[1].(string)
+		}
+		location := time.UTC
+		if len(arguments) > 2 {
+			location = loadLocation(arguments[2].(string))
+		}
+		instant, err := time.ParseInLocation(layout, arguments[0].(string), location)
+		if err != nil {
+			panic(NativeError{"Can't parse time '" + arguments[0].(string) + "' with layout '" + layout + "'."})
+		}
+		return &LoxTime{instant}
+	}))
+	module.DefineNative(native("duration", []ArgType{STRING_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		d, err := time.ParseDuration(arguments[0].(string))
+		if err != nil {
+			panic(NativeError{"Invalid duration '" + arguments[0].(string) + "'."})
+		}
+		return fromDuration(d)
+	}))
+	module.DefineNative(native("formatDuration", []ArgType{NUMBER_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		return toDuration(arguments[0].(float64)).String()
+	}))
+	module.DefineNative(native("sleep", []ArgType{NUMBER_ARG}, func(interpreter *Interpreter, arguments []any) any {
+		interpreter.scheduler.block(func() {
+			interpreter.clock.Sleep(toDuration(arguments[0].(float64)))
+		})
+		return nil
+	}))
+
+	module.Define("millisecond", 1.0)
+	module.Define("second", 1000.0)
+	module.Define("minute", 60*1000.0)
+	module.Define("hour", 60*60*1000.0)
+	module.Define("day", 24*60*60*1000.0)
+	return module
+}
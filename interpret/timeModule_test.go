@@ -0,0 +1,52 @@
+package interpret_test
+
+import "testing"
+
+func TestTimeFollowsClock(t *testing.T) {
+	expectOutput(t, `
+var start = time.now();
+var ticks = clock();
+print start;
+print start.weekday;
+time.sleep(1500);
+print time.now().since(start);
+print clock() - ticks;
+`, "2024-01-01T00:00:00Z\nMonday\n1500\n1500\n", nil)
+}
+
+func TestTimeValues(t *testing.T) {
+	expectOutput(t, `
+var d = time.date(2024, 2, 29, 13, 5, 7.25);
+print d;
+print d.format("2006-01-02 15:04");
+print d.millisecond;
+print d.add(time.day).format("Jan 2");
+print d.inZone("Asia/Tokyo").hour;
+print time.parse("2024-03-01", "2006-01-02") == time.date(2024, 3, 1);
+print time.parse("10:30", "15:04", "Europe/Paris").zone;
+print time.fromUnix(86400).day;
+`, "2024-02-29T13:05:07.25Z\n2024-02-29 13:05\n250\nMar 1\n22\ntrue\nEurope/Paris\n2\n", nil)
+}
+
+func TestDurations(t *testing.T) {
+	expectOutput(t, `
+print time.duration("1m30s");
+print time.formatDuration(90061000);
+print time.hour / time.minute;
+`, "90000\n25h1m1s\n60\n", nil)
+}
+
+func TestTimeErrors(t *testing.T) {
+	expectError(t, `
+time.parse("x");
+`, "Can't parse time 'x' with layout '2006-01-02T15:04:05Z07:00'.", 2, nil)
+	expectError(t, `
+time.now("Nowhere/City");
+`, "Unknown time zone 'Nowhere/City'.", 2, nil)
+	expectError(t, `
+time.duration("abc");
+`, "Invalid duration 'abc'.", 2, nil)
+	expectError(t, `
+time.now().since(1);
+`, "Argument 1 to since() must be a time.", 2, nil)
+}
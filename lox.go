@@ -2,9 +2,13 @@ package main
 
 import (
 	"bufio"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/kljablon/golox/ast"
 	"github.com/kljablon/golox/interpret"
@@ -16,12 +20,44 @@ import (
 var hadError bool = false
 var hadRuntimeError bool = false
 
+var fileRoot = flag.String("root", "", "confine the file natives to this directory")
+var fakeNow = flag.String("now", "", "run on a fake clock starting at this RFC 3339 time")
+var allowExec = flag.String("exec", "", "comma-separated commands scripts may run with exec()")
+var seed = flag.Int64("seed", 0, "seed the random number generator for a reproducible run")
+
+// scriptArgs are the command line arguments after the script path.
+var scriptArgs []string
+
+// promptInput is the reader the REPL reads lines from. Scripts run at the
+// prompt read their input from it too, so the two never split stdin.
+var promptInput *bufio.Reader
+
 func run(source string) {
 	scanner := parse.NewScanner(source)
 	tokens := scanner.ScanTokens()
 	parser := parse.NewParser(tokens)
 	statements := parser.Parse()
 	interpreter := interpret.NewInterpreter()
+	if err := interpreter.SetFileRoot(*fileRoot); err != nil {
+		log.Fatal(err)
+	}
+	interpreter.SetArgs(scriptArgs)
+	if promptInput != nil {
+		interpreter.SetInput(promptInput)
+	}
+	if *allowExec != "" {
+		interpreter.SetExecAllowlist(strings.Split(*allowExec, ","))
+	}
+	if *fakeNow != "" {
+		start, err := time.Parse(time.RFC3339, *fakeNow)
+		if err != nil {
+			log.Fatal(err)
+		}
+		interpreter.SetClock(interpret.NewFakeClock(start))
+	}
+	if flagSet("seed") {
+		interpreter.SetSeed(*seed)
+	}
 
 	if hadError {
 		os.Exit(65)
@@ -30,12 +66,18 @@ func run(source string) {
 		os.Exit(70)
 	}
 
-	resolver := resolve.NewResover()
+	resolver := resolve.NewResover(&interpreter)
 	resolver.ResolveStmts(statements)
 
 	// printer := AstPrinter{}
 	// fmt.Println(printer.Print(expression))
-	interpreter.Interpret(statements)
+	if err := interpreter.Interpret(statements); err != nil {
+		var exit interpret.ExitError
+		if errors.As(err, &exit) {
+			os.Exit(exit.Code)
+		}
+		runtimeError(err.(utils.RuntimeError))
+	}
 }
 
 func ReportError(line int, message string) {
@@ -55,8 +97,20 @@ func loxError(token ast.Token, message string) {
 	}
 }
 
+// flagSet reports whether the named flag was given on the command line, so a
+// flag can be set to its zero value.
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
 func runtimeError(err utils.RuntimeError) {
-	fmt.Printf(err.Message + "\n[line" + fmt.Sprintf("%d", err.Token.Line) + "]")
+	fmt.Fprintf(os.Stderr, "%s\n[line %d]\n", err.Message, err.Token.Line)
 	hadRuntimeError = true
 }
 
@@ -69,29 +123,31 @@ func runFile(path string) {
 	if hadError {
 		os.Exit(65)
 	}
+	if hadRuntimeError {
+		os.Exit(70)
+	}
 }
 
 func runPrompt() {
-	reader := bufio.NewReader(os.Stdin)
+	promptInput = bufio.NewReader(os.Stdin)
 	for {
 		fmt.Print("> ")
-		line, _ := reader.ReadString('\n')
+		line, _ := promptInput.ReadString('\n')
 		if line == "" {
 			break
 		}
 		run(line)
 		hadError = false
+		hadRuntimeError = false
 	}
 }
 
 func main() {
-	args := os.Args
-	if len(args) > 2 {
-		fmt.Println("Usage: jlox [script]")
-		os.Exit(64)
-	} else if len(args) == 2 {
-		fmt.Printf("runFile(%v)\n", args[1])
-		runFile(args[1])
+	flag.Parse()
+	args := flag.Args()
+	if len(args) >= 1 {
+		scriptArgs = args[1:]
+		runFile(args[0])
 	} else {
 		runPrompt()
 	}
@@ -44,6 +44,66 @@ func (a *AstPrinter) VisitExpr_Call(expr ast.Expr_Call) any {
 	return a.parenthesize("", expr.Callee)
 }
 
+func (a *AstPrinter) VisitExpr_List(expr ast.Expr_List) any {
+	return a.parenthesize("list", expr.Elements...)
+}
+
+func (a *AstPrinter) VisitExpr_Map(expr ast.Expr_Map) any {
+	entries := []ast.Expr{}
+	for i, key := range expr.Keys {
+		entries = append(entries, key, expr.Values[i])
+	}
+	return a.parenthesize("map", entries...)
+}
+
+func (a *AstPrinter) VisitExpr_SetLiteral(expr ast.Expr_SetLiteral) any {
+	return a.parenthesize("set", expr.Elements...)
+}
+
+func (a *AstPrinter) VisitExpr_Index(expr ast.Expr_Index) any {
+	return a.parenthesize("index", expr.Object, expr.Index)
+}
+
+func (a *AstPrinter) VisitExpr_IndexSet(expr ast.Expr_IndexSet) any {
+	return a.parenthesize("index=", expr.Object, expr.Index, expr.Value)
+}
+
+func (a *AstPrinter) VisitExpr_Get(expr ast.Expr_Get) any {
+	return a.parenthesize("."+expr.Name.Lexeme, expr.Object)
+}
+
+func (a *AstPrinter) VisitExpr_Set(expr ast.Expr_Set) any {
+	return a.parenthesize("."+expr.Name.Lexeme+"=", expr.Object, expr.Value)
+}
+
+func (a *AstPrinter) VisitExpr_Tuple(expr ast.Expr_Tuple) any {
+	return a.parenthesize("tuple", expr.Elements...)
+}
+
+func (a *AstPrinter) VisitExpr_Range(expr ast.Expr_Range) any {
+	return a.parenthesize(expr.Operator.Lexeme, expr.Start, expr.End)
+}
+
+func (a *AstPrinter) VisitExpr_Spread(expr ast.Expr_Spread) any {
+	return a.parenthesize("...", expr.Expression)
+}
+
+func (a *AstPrinter) VisitExpr_Spawn(expr ast.Expr_Spawn) any {
+	return a.parenthesize("spawn", expr.Call)
+}
+
+func (a *AstPrinter) VisitExpr_Await(expr ast.Expr_Await) any {
+	return a.parenthesize("await", expr.Value)
+}
+
+func (a *AstPrinter) VisitExpr_ListComprehension(expr ast.Expr_ListComprehension) any {
+	return a.parenthesize("for "+expr.Name.Lexeme, expr.Element, expr.Iterable)
+}
+
+func (a *AstPrinter) VisitExpr_MapComprehension(expr ast.Expr_MapComprehension) any {
+	return a.parenthesize("for "+expr.Name.Lexeme, expr.Key, expr.Value, expr.Iterable)
+}
+
 func (a *AstPrinter) Print(expr ast.Expr) string {
 	return expr.Accept(a).(string)
 }
@@ -50,6 +50,13 @@ func (p *Parser) advance() ast.Token {
 	return p.previous()
 }
 
+func (p *Parser) checkNext(ttype ast.TokenType) bool {
+	if p.isAtEnd() || p.tokens[p.current+1].TokenType == ast.EOF {
+		return false
+	}
+	return p.tokens[p.current+1].TokenType == ttype
+}
+
 func (p *Parser) isAtEnd() bool {
 	return p.peek().TokenType == ast.EOF
 }
@@ -79,6 +86,15 @@ func (p *Parser) declaration() ast.Stmt {
 	if p.match(ast.FUN) {
 		return p.function("function")
 	}
+	if p.match(ast.ASYNC) {
+		p.consume(ast.FUN, "Expect 'fun' after 'async'.")
+		function := p.function("function")
+		function.Async = true
+		return function
+	}
+	if p.match(ast.RECORD) {
+		return p.recordDeclaration()
+	}
 	if p.match(ast.VAR) {
 		return p.varDeclaration()
 	}
@@ -100,11 +116,11 @@ func (p *Parser) equality() (ast.Expr, error) {
 }
 
 func (p *Parser) comparison() (ast.Expr, error) {
-	expr, err := p.term()
+	expr, err := p.rangeExpr()
 
 	for p.match(ast.GREATER, ast.GREATER_EQUAL, ast.LESS, ast.LESS_EQUAL) {
 		operator := p.previous()
-		right, err := p.term()
+		right, err := p.rangeExpr()
 		if err != nil {
 			return nil, err
 		}
@@ -113,6 +129,25 @@ func (p *Parser) comparison() (ast.Expr, error) {
 	return expr, err
 }
 
+// rangeExpr parses a..b and a..<b. Ranges don't chain, so a..b..c is an
+// error rather than a range of ranges.
+func (p *Parser) rangeExpr() (ast.Expr, error) {
+	expr, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(ast.DOT_DOT, ast.DOT_DOT_LESS) {
+		operator := p.previous()
+		end, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		expr = &ast.Expr_Range{Start: expr, Operator: operator, End: end}
+	}
+	return expr, nil
+}
+
 func (p *Parser) term() (ast.Expr, error) {
 	expr, err := p.factor()
 
@@ -147,6 +182,22 @@ func (p *Parser) unary() (ast.Expr, error) {
 		right, _ := p.unary()
 		return &ast.Expr_Unary{Operator: operator, Right: right}, nil
 	}
+	if p.match(ast.SPAWN) {
+		keyword := p.previous()
+		call, ok := p.call().(*ast.Expr_Call)
+		if !ok {
+			return nil, p.pError(keyword, "Expect function call after 'spawn'.")
+		}
+		return &ast.Expr_Spawn{Keyword: keyword, Call: call}, nil
+	}
+	if p.match(ast.AWAIT) {
+		keyword := p.previous()
+		value, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &ast.Expr_Await{Keyword: keyword, Value: value}, nil
+	}
 	return p.call(), nil
 }
 
@@ -159,6 +210,14 @@ func (p *Parser) call() ast.Expr {
 	for {
 		if p.match(ast.LEFT_PAREN) {
 			expr = p.finishCall(expr)
+		} else if p.match(ast.DOT) {
+			name, err := p.consume(ast.IDENTIFIER, "Expect property name after '.'.")
+			if err != nil {
+				log.Fatal("at call: ", err)
+			}
+			expr = &ast.Expr_Get{Object: expr, Name: *name}
+		} else if p.match(ast.LEFT_BRACKET) {
+			expr = p.finishIndex(expr)
 		} else {
 			break
 		}
@@ -166,25 +225,35 @@ func (p *Parser) call() ast.Expr {
 	return expr
 }
 
+func (p *Parser) finishIndex(object ast.Expr) ast.Expr {
+	bracket := p.previous()
+	index := p.expression()
+	_, err := p.consume(ast.RIGHT_BRACKET, "Expect ']' after index.")
+	if err != nil {
+		log.Fatal("at finishIndex: ", err)
+	}
+	return &ast.Expr_Index{Object: object, Bracket: bracket, Index: index}
+}
+
 func (p *Parser) finishCall(callee ast.Expr) ast.Expr {
 	arguments := []ast.Expr{}
 	if !p.check(ast.RIGHT_PAREN) {
 		if len(arguments) >= 255 {
 			log.Fatal(p.peek(), "Can't have more than 255 arguments.")
 		}
-		arguments = append(arguments, p.expression())
+		arguments = append(arguments, p.element())
 		for p.match(ast.COMMA) {
 			if len(arguments) >= 255 {
 				log.Fatal(p.peek(), "Can't have more than 255 arguments.")
 			}
-			arguments = append(arguments, p.expression())
+			arguments = append(arguments, p.element())
 		}
 	}
 	paren, err := p.consume(ast.RIGHT_PAREN, "Expect ')' after arguments.")
 	if err != nil {
 		log.Fatal("at finishCall: %w", err)
 	}
-	return ast.Expr_Call{Callee: callee, Paren: *paren, Arguments: arguments}
+	return &ast.Expr_Call{Callee: callee, Paren: *paren, Arguments: arguments}
 }
 
 func (p *Parser) primary() (ast.Expr, error) {
@@ -205,16 +274,174 @@ func (p *Parser) primary() (ast.Expr, error) {
 		return &ast.Expr_Variable{Name: p.previous()}, nil
 	}
 	if p.match(ast.LEFT_PAREN) {
+		paren := p.previous()
+		if p.match(ast.RIGHT_PAREN) {
+			return &ast.Expr_Tuple{Paren: paren, Elements: []ast.Expr{}}, nil
+		}
 		expr := p.expression()
+		if p.match(ast.COMMA) {
+			return p.tuple(paren, expr)
+		}
 		_, err := p.consume(ast.RIGHT_PAREN, "Expect ')' after expression.")
 		if err != nil {
 			return nil, err
 		}
 		return &ast.Expr_Grouping{Expression: expr}, nil
 	}
+	if p.match(ast.LEFT_BRACKET) {
+		return p.listLiteral()
+	}
+	if p.match(ast.LEFT_BRACE) {
+		return p.mapLiteral()
+	}
 	return nil, p.pError(p.peek(), "Expect expression.")
 }
 
+// tuple parses the rest of a parenthesized, comma separated list once the
+// first element and comma have been read. A trailing comma is allowed, which
+// is how a one-element tuple is written: (x,).
+func (p *Parser) tuple(paren ast.Token, first ast.Expr) (ast.Expr, error) {
+	elements := []ast.Expr{first}
+	for !p.check(ast.RIGHT_PAREN) {
+		elements = append(elements, p.expression())
+		if !p.match(ast.COMMA) {
+			break
+		}
+	}
+	_, err := p.consume(ast.RIGHT_PAREN, "Expect ')' after tuple elements.")
+	if err != nil {
+		return nil, err
+	}
+	return &ast.Expr_Tuple{Paren: paren, Elements: elements}, nil
+}
+
+// element parses a list element or call argument, either of which may be
+// a spread: ...xs.
+func (p *Parser) element() ast.Expr {
+	if p.match(ast.DOT_DOT_DOT) {
+		return &ast.Expr_Spread{Ellipsis: p.previous(), Expression: p.expression()}
+	}
+	return p.expression()
+}
+
+// comprehensionClause parses the "for name in iterable if condition" tail
+// of a comprehension, after the 'for' keyword. The condition may be nil.
+func (p *Parser) comprehensionClause() (ast.Token, ast.Expr, ast.Expr, error) {
+	name, err := p.consume(ast.IDENTIFIER, "Expect loop variable after 'for'.")
+	if err != nil {
+		return ast.Token{}, nil, nil, err
+	}
+	_, err = p.consume(ast.IN, "Expect 'in' after loop variable.")
+	if err != nil {
+		return ast.Token{}, nil, nil, err
+	}
+	iterable := p.expression()
+	var condition ast.Expr
+	if p.match(ast.IF) {
+		condition = p.expression()
+	}
+	return *name, iterable, condition, nil
+}
+
+func (p *Parser) listLiteral() (ast.Expr, error) {
+	bracket := p.previous()
+	elements := []ast.Expr{}
+	for !p.check(ast.RIGHT_BRACKET) {
+		elements = append(elements, p.element())
+		if len(elements) == 1 && p.match(ast.FOR) {
+			return p.listComprehension(bracket, elements[0])
+		}
+		if !p.match(ast.COMMA) {
+			break
+		}
+	}
+	_, err := p.consume(ast.RIGHT_BRACKET, "Expect ']' after list elements.")
+	if err != nil {
+		return nil, err
+	}
+	return &ast.Expr_List{Bracket: bracket, Elements: elements}, nil
+}
+
+func (p *Parser) listComprehension(bracket ast.Token, element ast.Expr) (ast.Expr, error) {
+	name, iterable, condition, err := p.comprehensionClause()
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(ast.RIGHT_BRACKET, "Expect ']' after list comprehension.")
+	if err != nil {
+		return nil, err
+	}
+	return &ast.Expr_ListComprehension{
+		Bracket:   bracket,
+		Element:   element,
+		Name:      name,
+		Iterable:  iterable,
+		Condition: condition,
+	}, nil
+}
+
+// mapLiteral parses {k: v, ...}, or a set literal {a, b, ...} when the first
+// element isn't followed by ':'. {} is an empty map.
+func (p *Parser) mapLiteral() (ast.Expr, error) {
+	brace := p.previous()
+	keys := []ast.Expr{}
+	values := []ast.Expr{}
+	for !p.check(ast.RIGHT_BRACE) {
+		key := p.element()
+		if len(keys) == 0 && !p.check(ast.COLON) {
+			return p.setLiteral(brace, key)
+		}
+		keys = append(keys, key)
+		_, err := p.consume(ast.COLON, "Expect ':' after map key.")
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, p.expression())
+		if len(keys) == 1 && p.match(ast.FOR) {
+			return p.mapComprehension(brace, keys[0], values[0])
+		}
+		if !p.match(ast.COMMA) {
+			break
+		}
+	}
+	_, err := p.consume(ast.RIGHT_BRACE, "Expect '}' after map entries.")
+	if err != nil {
+		return nil, err
+	}
+	return &ast.Expr_Map{Brace: brace, Keys: keys, Values: values}, nil
+}
+
+func (p *Parser) setLiteral(brace ast.Token, first ast.Expr) (ast.Expr, error) {
+	elements := []ast.Expr{first}
+	for p.match(ast.COMMA) && !p.check(ast.RIGHT_BRACE) {
+		elements = append(elements, p.element())
+	}
+	_, err := p.consume(ast.RIGHT_BRACE, "Expect '}' after set elements.")
+	if err != nil {
+		return nil, err
+	}
+	return &ast.Expr_SetLiteral{Brace: brace, Elements: elements}, nil
+}
+
+func (p *Parser) mapComprehension(brace ast.Token, key ast.Expr, value ast.Expr) (ast.Expr, error) {
+	name, iterable, condition, err := p.comprehensionClause()
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(ast.RIGHT_BRACE, "Expect '}' after map comprehension.")
+	if err != nil {
+		return nil, err
+	}
+	return &ast.Expr_MapComprehension{
+		Brace:     brace,
+		Key:       key,
+		Value:     value,
+		Name:      name,
+		Iterable:  iterable,
+		Condition: condition,
+	}, nil
+}
+
 type ParseError struct {
 	msg string
 }
@@ -242,6 +469,9 @@ func (p *Parser) statement() ast.Stmt {
 	if p.match(ast.RETURN) {
 		return p.returnStatement()
 	}
+	if p.match(ast.SELECT) {
+		return p.selectStatement()
+	}
 	if p.match(ast.WHILE) {
 		return p.whileStatement()
 	}
@@ -295,6 +525,9 @@ func (p *Parser) whileStatement() ast.Stmt_While {
 
 func (p *Parser) forStatement() ast.Stmt {
 	p.consume(ast.LEFT_PAREN, "Expect '(' after 'for'.")
+	if p.check(ast.IDENTIFIER) && p.checkNext(ast.IN) {
+		return p.forInStatement()
+	}
 	var initializer ast.Stmt
 	if p.match(ast.SEMICOLON) {
 		initializer = nil
@@ -324,7 +557,7 @@ func (p *Parser) forStatement() ast.Stmt {
 	}
 
 	if condition == nil {
-		condition = ast.Expr_Literal{Value: true}
+		condition = &ast.Expr_Literal{Value: true}
 	}
 	body = ast.Stmt_While{Condition: condition, Body: body}
 
@@ -338,6 +571,75 @@ func (p *Parser) forStatement() ast.Stmt {
 
 }
 
+func (p *Parser) forInStatement() ast.Stmt_ForIn {
+	name := p.advance()
+	p.consume(ast.IN, "Expect 'in' after loop variable.")
+	iterable := p.expression()
+	p.consume(ast.RIGHT_PAREN, "Expect ')' after for-in clause.")
+	body := p.statement()
+
+	return ast.Stmt_ForIn{Name: name, Iterable: iterable, Body: body}
+}
+
+// selectStatement parses the cases of a select, each one of
+//
+//	case name = recv(channel) => statement
+//	case recv(channel) => statement
+//	case send(channel, value) => statement
+//	case timeout(milliseconds) => statement
+//	default => statement
+func (p *Parser) selectStatement() ast.Stmt_Select {
+	stmt := ast.Stmt_Select{Keyword: p.previous()}
+	p.consume(ast.LEFT_BRACE, "Expect '{' after 'select'.")
+	for !p.check(ast.RIGHT_BRACE) && !p.isAtEnd() {
+		if p.check(ast.IDENTIFIER) && p.peek().Lexeme == "default" {
+			keyword := p.advance()
+			if stmt.Default != nil {
+				p.pError(keyword, "A select can only have one default case.")
+			}
+			p.consume(ast.ARROW, "Expect '=>' after 'default'.")
+			stmt.Default = p.statement()
+			continue
+		}
+		p.consume(ast.CASE, "Expect 'case' or 'default' in select.")
+		stmt.Cases = append(stmt.Cases, p.selectCase())
+	}
+	p.consume(ast.RIGHT_BRACE, "Expect '}' after select cases.")
+	return stmt
+}
+
+func (p *Parser) selectCase() ast.SelectCase {
+	var name *ast.Token
+	if p.check(ast.IDENTIFIER) && p.checkNext(ast.EQUAL) {
+		token := p.advance()
+		p.advance()
+		name = &token
+	}
+	kind, err := p.consume(ast.IDENTIFIER, "Expect recv, send or timeout after 'case'.")
+	if err != nil {
+		log.Fatalf("%v at selectCase()", err)
+	}
+	selectCase := ast.SelectCase{Kind: *kind, Name: name}
+	p.consume(ast.LEFT_PAREN, "Expect '(' after '"+kind.Lexeme+"'.")
+	switch kind.Lexeme {
+	case "recv", "timeout":
+		selectCase.Operand = p.expression()
+	case "send":
+		selectCase.Operand = p.expression()
+		p.consume(ast.COMMA, "Expect ',' after channel.")
+		selectCase.Value = p.expression()
+	default:
+		p.pError(*kind, "Expect recv, send or timeout after 'case'.")
+	}
+	if name != nil && kind.Lexeme != "recv" {
+		p.pError(*name, "Only a recv case can assign a variable.")
+	}
+	p.consume(ast.RIGHT_PAREN, "Expect ')' after '"+kind.Lexeme+"' arguments.")
+	p.consume(ast.ARROW, "Expect '=>' after select case.")
+	selectCase.Body = p.statement()
+	return selectCase
+}
+
 func (p *Parser) expressionStatement() ast.Stmt_Expression {
 	expr := p.expression()
 	p.consume(ast.SEMICOLON, "Expect ';' after expression.")
@@ -347,7 +649,7 @@ func (p *Parser) expressionStatement() ast.Stmt_Expression {
 func (p *Parser) function(kind string) ast.Stmt_Function {
 	name, err := p.consume(ast.IDENTIFIER, fmt.Sprintf("Expect %s name.", kind))
 	if err != nil {
-		log.Fatalf("%w at varDeclaration()", err)
+		log.Fatalf("%v at function()", err)
 	}
 	p.consume(ast.LEFT_PAREN, fmt.Sprintf("Expect ( after %s name.", kind))
 	parameters := []ast.Token{}
@@ -358,7 +660,7 @@ func (p *Parser) function(kind string) ast.Stmt_Function {
 			}
 			param, err := p.consume(ast.IDENTIFIER, "Expect parameter name.")
 			if err != nil {
-				log.Fatalf("%w at varDeclaration()", err)
+				log.Fatalf("%v at function()", err)
 			}
 			parameters = append(parameters, *param)
 			if !p.match(ast.COMMA) {
@@ -372,6 +674,33 @@ func (p *Parser) function(kind string) ast.Stmt_Function {
 	return ast.Stmt_Function{Name: *name, Params: parameters, Body: body}
 }
 
+func (p *Parser) recordDeclaration() ast.Stmt_Record {
+	name, err := p.consume(ast.IDENTIFIER, "Expect record name.")
+	if err != nil {
+		log.Fatalf("%v at recordDeclaration()", err)
+	}
+	p.consume(ast.LEFT_PAREN, "Expect '(' after record name.")
+	fields := []ast.Token{}
+	if !p.check(ast.RIGHT_PAREN) {
+		for {
+			if len(fields) >= 255 {
+				log.Fatal("Can't have more than 255 fields.")
+			}
+			field, err := p.consume(ast.IDENTIFIER, "Expect field name.")
+			if err != nil {
+				log.Fatalf("%v at recordDeclaration()", err)
+			}
+			fields = append(fields, *field)
+			if !p.match(ast.COMMA) {
+				break
+			}
+		}
+	}
+	p.consume(ast.RIGHT_PAREN, "Expect ')' after fields.")
+	p.consume(ast.SEMICOLON, "Expect ';' after record declaration.")
+	return ast.Stmt_Record{Name: *name, Fields: fields}
+}
+
 func (p *Parser) block() []ast.Stmt {
 	var statements []ast.Stmt
 	for !p.check(ast.RIGHT_BRACE) && !p.isAtEnd() {
@@ -404,6 +733,12 @@ func (p *Parser) assignment() ast.Expr {
 			name := expr.Name
 			return &ast.Expr_Assign{Name: name, Value: value}
 		}
+		if expr, ok := expr.(*ast.Expr_Get); ok {
+			return &ast.Expr_Set{Object: expr.Object, Name: expr.Name, Value: value}
+		}
+		if expr, ok := expr.(*ast.Expr_Index); ok {
+			return &ast.Expr_IndexSet{Object: expr.Object, Bracket: expr.Bracket, Index: expr.Index, Value: value}
+		}
 		err := utils.NewRuntimeError(equals, "Invalid assignment target.")
 		log.Fatal(err)
 	}
@@ -415,7 +750,7 @@ func (p *Parser) or() ast.Expr {
 	for p.match(ast.OR) {
 		operator := p.previous()
 		right := p.and()
-		expr = ast.Expr_Logical{
+		expr = &ast.Expr_Logical{
 			Left:     expr,
 			Operator: operator,
 			Right:    right,
@@ -436,7 +771,7 @@ func (p *Parser) and() ast.Expr {
 		if err != nil {
 			log.Fatal("at and(): ", err)
 		}
-		expr = ast.Expr_Logical{
+		expr = &ast.Expr_Logical{
 			Left:     expr,
 			Operator: operator,
 			Right:    right,
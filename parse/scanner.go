@@ -21,16 +21,23 @@ func NewScanner(source string) Scanner {
 
 	keywords := map[string]ast.TokenType{
 		"and":    ast.AND,
+		"async":  ast.ASYNC,
+		"await":  ast.AWAIT,
+		"case":   ast.CASE,
 		"class":  ast.CLASS,
 		"else":   ast.ELSE,
 		"false":  ast.FALSE,
 		"for":    ast.FOR,
 		"fun":    ast.FUN,
 		"if":     ast.IF,
+		"in":     ast.IN,
 		"nil":    ast.NIL,
 		"or":     ast.OR,
 		"print":  ast.PRINT,
+		"record": ast.RECORD,
 		"return": ast.RETURN,
+		"select": ast.SELECT,
+		"spawn":  ast.SPAWN,
 		"super":  ast.SUPER,
 		"this":   ast.THIS,
 		"true":   ast.TRUE,
@@ -50,7 +57,8 @@ func (s *Scanner) ScanTokens() []ast.Token {
 		TokenType: ast.EOF,
 		Lexeme:    "",
 		Literal:   nil,
-		Line:      s.line})
+		Line:      s.line,
+		Offset:    s.current})
 	return s.tokens
 }
 
@@ -69,10 +77,26 @@ func (s *Scanner) scanToken() {
 		s.addToken(ast.LEFT_BRACE, nil)
 	case '}':
 		s.addToken(ast.RIGHT_BRACE, nil)
+	case '[':
+		s.addToken(ast.LEFT_BRACKET, nil)
+	case ']':
+		s.addToken(ast.RIGHT_BRACKET, nil)
+	case ':':
+		s.addToken(ast.COLON, nil)
 	case ',':
 		s.addToken(ast.COMMA, nil)
 	case '.':
-		s.addToken(ast.DOT, nil)
+		if s.match('.') {
+			if s.match('<') {
+				s.addToken(ast.DOT_DOT_LESS, nil)
+			} else if s.match('.') {
+				s.addToken(ast.DOT_DOT_DOT, nil)
+			} else {
+				s.addToken(ast.DOT_DOT, nil)
+			}
+		} else {
+			s.addToken(ast.DOT, nil)
+		}
 	case '-':
 		s.addToken(ast.MINUS, nil)
 	case '+':
@@ -90,6 +114,8 @@ func (s *Scanner) scanToken() {
 	case '=':
 		if s.match('=') {
 			s.addToken(ast.EQUAL_EQUAL, nil)
+		} else if s.match('>') {
+			s.addToken(ast.ARROW, nil)
 		} else {
 			s.addToken(ast.EQUAL, nil)
 		}
@@ -148,7 +174,7 @@ func (s *Scanner) scanToken() {
 
 func (s *Scanner) addToken(ttype ast.TokenType, literal any) {
 	text := s.source[s.start:s.current]
-	s.tokens = append(s.tokens, ast.Token{TokenType: ttype, Lexeme: text, Literal: literal, Line: s.line})
+	s.tokens = append(s.tokens, ast.Token{TokenType: ttype, Lexeme: text, Literal: literal, Line: s.line, Offset: s.start})
 }
 
 func (s *Scanner) match(expected rune) bool {
@@ -10,7 +10,7 @@ import (
 )
 
 type Resolver struct {
-	interpreter     interpret.Interpreter
+	interpreter     *interpret.Interpreter
 	scopes          []map[string]bool
 	currentFunction FunctionType
 }
@@ -20,10 +20,10 @@ type FunctionType int
 const (
 	NONE FunctionType = iota
 	FUNCTION
+	ASYNC_FUNCTION
 )
 
-func NewResover() Resolver {
-	interpreter := interpret.NewInterpreter()
+func NewResover(interpreter *interpret.Interpreter) Resolver {
 	scopes := []map[string]bool{}
 	return Resolver{
 		interpreter, scopes, NONE,
@@ -52,6 +52,11 @@ func (r *Resolver) VisitStmt_Print(stmt ast.Stmt_Print) {
 	r.resolveExpr(stmt.Expression)
 }
 
+func (r *Resolver) VisitStmt_Record(stmt ast.Stmt_Record) {
+	r.declare(stmt.Name)
+	r.define(stmt.Name)
+}
+
 func (r *Resolver) VisitStmt_Return(stmt ast.Stmt_Return) {
 	if r.currentFunction == NONE {
 		log.Fatal("Can't return from top-level code.")
@@ -66,10 +71,42 @@ func (r *Resolver) VisitStmt_While(stmt ast.Stmt_While) {
 	r.resolveStmt(stmt.Body)
 }
 
+func (r *Resolver) VisitStmt_ForIn(stmt ast.Stmt_ForIn) {
+	r.resolveExpr(stmt.Iterable)
+	r.beginScope()
+	r.declare(stmt.Name)
+	r.define(stmt.Name)
+	r.resolveStmt(stmt.Body)
+	r.endScope()
+}
+
+func (r *Resolver) VisitStmt_Select(stmt ast.Stmt_Select) {
+	for _, selectCase := range stmt.Cases {
+		r.resolveExpr(selectCase.Operand)
+		if selectCase.Value != nil {
+			r.resolveExpr(selectCase.Value)
+		}
+		r.beginScope()
+		if selectCase.Name != nil {
+			r.declare(*selectCase.Name)
+			r.define(*selectCase.Name)
+		}
+		r.resolveStmt(selectCase.Body)
+		r.endScope()
+	}
+	if stmt.Default != nil {
+		r.resolveStmt(stmt.Default)
+	}
+}
+
 func (r *Resolver) VisitStmt_Function(stmt ast.Stmt_Function) {
 	r.declare(stmt.Name)
 	r.define(stmt.Name)
-	r.resolveFunction(stmt, FUNCTION)
+	if stmt.Async {
+		r.resolveFunction(stmt, ASYNC_FUNCTION)
+	} else {
+		r.resolveFunction(stmt, FUNCTION)
+	}
 }
 
 func (r *Resolver) VisitStmt_Var(stmt ast.Stmt_Var) {
@@ -120,6 +157,112 @@ func (r *Resolver) VisitExpr_Unary(expr ast.Expr_Unary) any {
 	return nil
 }
 
+func (r *Resolver) VisitExpr_List(expr ast.Expr_List) any {
+	for _, element := range expr.Elements {
+		r.resolveExpr(element)
+	}
+	return nil
+}
+
+func (r *Resolver) VisitExpr_Map(expr ast.Expr_Map) any {
+	for i, key := range expr.Keys {
+		r.resolveExpr(key)
+		r.resolveExpr(expr.Values[i])
+	}
+	return nil
+}
+
+func (r *Resolver) VisitExpr_SetLiteral(expr ast.Expr_SetLiteral) any {
+	for _, element := range expr.Elements {
+		r.resolveExpr(element)
+	}
+	return nil
+}
+
+func (r *Resolver) VisitExpr_Index(expr ast.Expr_Index) any {
+	r.resolveExpr(expr.Object)
+	r.resolveExpr(expr.Index)
+	return nil
+}
+
+func (r *Resolver) VisitExpr_IndexSet(expr ast.Expr_IndexSet) any {
+	r.resolveExpr(expr.Value)
+	r.resolveExpr(expr.Object)
+	r.resolveExpr(expr.Index)
+	return nil
+}
+
+func (r *Resolver) VisitExpr_Get(expr ast.Expr_Get) any {
+	r.resolveExpr(expr.Object)
+	return nil
+}
+
+func (r *Resolver) VisitExpr_Set(expr ast.Expr_Set) any {
+	r.resolveExpr(expr.Value)
+	r.resolveExpr(expr.Object)
+	return nil
+}
+
+func (r *Resolver) VisitExpr_Tuple(expr ast.Expr_Tuple) any {
+	for _, element := range expr.Elements {
+		r.resolveExpr(element)
+	}
+	return nil
+}
+
+func (r *Resolver) VisitExpr_Range(expr ast.Expr_Range) any {
+	r.resolveExpr(expr.Start)
+	r.resolveExpr(expr.End)
+	return nil
+}
+
+func (r *Resolver) VisitExpr_Spread(expr ast.Expr_Spread) any {
+	r.resolveExpr(expr.Expression)
+	return nil
+}
+
+func (r *Resolver) VisitExpr_Spawn(expr ast.Expr_Spawn) any {
+	r.resolveExpr(expr.Call)
+	return nil
+}
+
+// VisitExpr_Await allows await in async functions and in top-level code,
+// where it runs the event loop until the promise settles.
+func (r *Resolver) VisitExpr_Await(expr ast.Expr_Await) any {
+	if r.currentFunction == FUNCTION {
+		log.Fatal("Can't use 'await' outside an async function.")
+	}
+	r.resolveExpr(expr.Value)
+	return nil
+}
+
+func (r *Resolver) VisitExpr_ListComprehension(expr ast.Expr_ListComprehension) any {
+	r.resolveExpr(expr.Iterable)
+	r.beginScope()
+	r.declare(expr.Name)
+	r.define(expr.Name)
+	if expr.Condition != nil {
+		r.resolveExpr(expr.Condition)
+	}
+	r.resolveExpr(expr.Element)
+	r.endScope()
+	return nil
+}
+
+func (r *Resolver) VisitExpr_MapComprehension(expr ast.Expr_MapComprehension) any {
+	r.resolveExpr(expr.Iterable)
+	r.beginScope()
+	r.declare(expr.Name)
+	r.define(expr.Name)
+	if expr.Condition != nil {
+		r.resolveExpr(expr.Condition)
+	}
+	r.resolveExpr(expr.Key)
+	r.resolveExpr(expr.Value)
+	r.endScope()
+	return nil
+}
+
 func (r *Resolver) VisitExpr_Variable(expr ast.Expr_Variable) any {
 	if len(r.scopes) > 0 {
 		scope, err := r.peekScopes()
@@ -27,6 +27,12 @@ func IsTruthy(object any) bool {
 	}
 }
 
+// Equatable is implemented by values that compare by their contents rather
+// than by identity.
+type Equatable interface {
+	Equals(other any) bool
+}
+
 func IsEqual(a any, b any) bool {
 	if a == nil && b == nil {
 		return true
@@ -34,6 +40,9 @@ func IsEqual(a any, b any) bool {
 	if a == nil {
 		return false
 	}
+	if a, ok := a.(Equatable); ok {
+		return a.Equals(b)
+	}
 	return a == b
 }
 